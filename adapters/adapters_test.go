@@ -108,3 +108,121 @@ func TestAdapters(t *testing.T) {
 		})
 	}
 }
+
+func TestAdaptersUnmatched(t *testing.T) {
+	config := func() huma.Config {
+		return huma.DefaultConfig("Test", "1.0.0")
+	}
+
+	// Each adapter returns a function to set up unmatched route handling, which
+	// is called after registering the operations.
+	for _, adapter := range []struct {
+		name string
+		new  func() (huma.API, func())
+	}{
+		{"chi", func() (huma.API, func()) {
+			r := chi.NewMux()
+			api := humachi.New(r, config())
+			return api, func() { humachi.HandleUnmatched(r, api) }
+		}},
+		{"echo", func() (huma.API, func()) {
+			r := echo.New()
+			api := humaecho.New(r, config())
+			return api, func() { humaecho.HandleUnmatched(r, api) }
+		}},
+		{"fiber", func() (huma.API, func()) {
+			r := fiber.New()
+			api := humafiber.New(r, config())
+			return api, func() { humafiber.HandleUnmatched(r, api) }
+		}},
+		{"gin", func() (huma.API, func()) {
+			r := gin.New()
+			api := humagin.New(r, config())
+			return api, func() { humagin.HandleUnmatched(r, api) }
+		}},
+		{"httprouter", func() (huma.API, func()) {
+			r := httprouter.New()
+			api := humahttprouter.New(r, config())
+			return api, func() { humahttprouter.HandleUnmatched(r, api) }
+		}},
+		{"mux", func() (huma.API, func()) {
+			r := mux.NewRouter()
+			api := humamux.New(r, config())
+			return api, func() { humamux.HandleUnmatched(r, api) }
+		}},
+		{"bunrouter", func() (huma.API, func()) {
+			var api huma.API
+			r := bunrouter.New(
+				bunrouter.WithNotFoundHandler(humabunrouter.UnmatchedHandler(&api)),
+				bunrouter.WithMethodNotAllowedHandler(humabunrouter.UnmatchedHandler(&api)),
+			)
+			api = humabunrouter.New(r, config())
+			return api, func() {}
+		}},
+	} {
+		t.Run(adapter.name, func(t *testing.T) {
+			a, handleUnmatched := adapter.new()
+			api := humatest.Wrap(t, a)
+			for _, method := range []string{http.MethodPut, http.MethodPost} {
+				huma.Register(api, huma.Operation{
+					OperationID: method + "-test",
+					Method:      method,
+					Path:        "/{group}",
+				}, testHandler)
+			}
+			handleUnmatched()
+
+			resp := api.Delete("/foo")
+			assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+			assert.Equal(t, "POST, PUT", resp.Header().Get("Allow"))
+			assert.Equal(t, "application/problem+json", resp.Header().Get("Content-Type"))
+			assert.Contains(t, resp.Body.String(), "method DELETE not allowed")
+
+			resp = api.Get("/foo/bar")
+			assert.Equal(t, http.StatusNotFound, resp.Code)
+			assert.Empty(t, resp.Header().Get("Allow"))
+			assert.Equal(t, "application/problem+json", resp.Header().Get("Content-Type"))
+		})
+	}
+}
+
+func TestAdaptersKeepFallbacks(t *testing.T) {
+	// Creating an API must not replace the router's own fallback handlers, e.g.
+	// to serve a single page app.
+	fallback := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}
+
+	chiRouter := chi.NewMux()
+	chiRouter.NotFound(fallback)
+
+	ginEngine := gin.New()
+	ginEngine.NoRoute(gin.WrapF(fallback))
+
+	echoRouter := echo.New()
+	echoRouter.HTTPErrorHandler = func(err error, c echo.Context) {
+		c.NoContent(http.StatusTeapot)
+	}
+
+	httpRouter := httprouter.New()
+	httpRouter.NotFound = http.HandlerFunc(fallback)
+
+	muxRouter := mux.NewRouter()
+	muxRouter.NotFoundHandler = http.HandlerFunc(fallback)
+
+	for _, adapter := range []struct {
+		name string
+		api  huma.API
+	}{
+		{"chi", humachi.New(chiRouter, huma.DefaultConfig("Test", "1.0.0"))},
+		{"echo", humaecho.New(echoRouter, huma.DefaultConfig("Test", "1.0.0"))},
+		{"gin", humagin.NewWithGroup(ginEngine, ginEngine.Group("/api"), huma.DefaultConfig("Test", "1.0.0"))},
+		{"httprouter", humahttprouter.New(httpRouter, huma.DefaultConfig("Test", "1.0.0"))},
+		{"mux", humamux.New(muxRouter, huma.DefaultConfig("Test", "1.0.0"))},
+	} {
+		t.Run(adapter.name, func(t *testing.T) {
+			api := humatest.Wrap(t, adapter.api)
+			assert.Equal(t, http.StatusTeapot, api.Get("/app/page").Code)
+		})
+	}
+}
@@ -265,3 +265,21 @@ func NewCompat(r *bunrouter.CompatRouter, config huma.Config) huma.API {
 func New(r *bunrouter.Router, config huma.Config) huma.API {
 	return huma.NewAPI(config, NewAdapter(r))
 }
+
+// UnmatchedHandler returns a handler which writes errors using
+// `huma.HandleUnmatched`. BunRouter only accepts not found and method not
+// allowed handlers when the router is created, before the API exists, so the
+// API is passed by reference:
+//
+//	var api huma.API
+//	router := bunrouter.New(
+//		bunrouter.WithNotFoundHandler(humabunrouter.UnmatchedHandler(&api)),
+//		bunrouter.WithMethodNotAllowedHandler(humabunrouter.UnmatchedHandler(&api)),
+//	)
+//	api = humabunrouter.New(router, huma.DefaultConfig("My API", "1.0.0"))
+func UnmatchedHandler(api *huma.API) bunrouter.HandlerFunc {
+	return func(w http.ResponseWriter, r bunrouter.Request) error {
+		huma.HandleUnmatched(*api, NewContext(nil, r, w))
+		return nil
+	}
+}
@@ -134,3 +134,17 @@ func NewAdapter(r chi.Router) huma.Adapter {
 func New(r chi.Router, config huma.Config) huma.API {
 	return huma.NewAPI(config, &chiAdapter{router: r})
 }
+
+// HandleUnmatched sets the router's not found and method not allowed handlers
+// to write errors using `huma.HandleUnmatched`, replacing any existing ones.
+//
+//	router := chi.NewMux()
+//	api := humachi.New(router, huma.DefaultConfig("My API", "1.0.0"))
+//	humachi.HandleUnmatched(router, api)
+func HandleUnmatched(r chi.Router, api huma.API) {
+	unmatched := func(w http.ResponseWriter, r *http.Request) {
+		huma.HandleUnmatched(api, &chiContext{r: r, w: w})
+	}
+	r.NotFound(unmatched)
+	r.MethodNotAllowed(unmatched)
+}
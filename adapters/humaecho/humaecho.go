@@ -136,3 +136,26 @@ func New(r *echo.Echo, config huma.Config) huma.API {
 func NewWithGroup(r *echo.Echo, g *echo.Group, config huma.Config) huma.API {
 	return huma.NewAPI(config, &echoAdapter{Handler: r, router: g})
 }
+
+// HandleUnmatched wraps the router's error handler so that requests which do
+// not match a route are written using `huma.HandleUnmatched`. Other errors
+// are still passed to the existing error handler.
+//
+//	e := echo.New()
+//	api := humaecho.New(e, huma.DefaultConfig("My API", "1.0.0"))
+//	humaecho.HandleUnmatched(e, api)
+func HandleUnmatched(r *echo.Echo, api huma.API) {
+	next := r.HTTPErrorHandler
+	if next == nil {
+		next = r.DefaultHTTPErrorHandler
+	}
+	r.HTTPErrorHandler = func(err error, c echo.Context) {
+		if err == echo.ErrNotFound || err == echo.ErrMethodNotAllowed {
+			// Echo may have already set its own `Allow` header.
+			c.Response().Header().Del("Allow")
+			huma.HandleUnmatched(api, &echoCtx{orig: c})
+			return
+		}
+		next(err, c)
+	}
+}
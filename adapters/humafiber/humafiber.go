@@ -160,3 +160,19 @@ func New(r *fiber.App, config huma.Config) huma.API {
 func NewWithGroup(r *fiber.App, g fiber.Router, config huma.Config) huma.API {
 	return huma.NewAPI(config, &fiberAdapter{tester: r, router: g})
 }
+
+// HandleUnmatched adds a catch-all handler to the router which writes errors
+// using `huma.HandleUnmatched`. Fiber has no separate not found or method not
+// allowed handlers, so this must be called *after* registering all routes,
+// including any of your own fallbacks, as routes are matched in order.
+//
+//	app := fiber.New()
+//	api := humafiber.New(app, huma.DefaultConfig("My API", "1.0.0"))
+//	huma.Get(api, "/things", listThings)
+//	humafiber.HandleUnmatched(app, api)
+func HandleUnmatched(r fiber.Router, api huma.API) {
+	r.Use(func(c *fiber.Ctx) error {
+		huma.HandleUnmatched(api, &fiberCtx{orig: c})
+		return nil
+	})
+}
@@ -154,3 +154,17 @@ func New(m Mux, config huma.Config) huma.API {
 func NewWithPrefix(m Mux, prefix string, config huma.Config) huma.API {
 	return huma.NewAPI(config, &goAdapter{m, prefix})
 }
+
+// HandleUnmatched sets the router's not found and method not allowed handlers
+// to write errors using `huma.HandleUnmatched`, replacing any existing ones.
+//
+//	mux := flow.New()
+//	api := humaflow.New(mux, huma.DefaultConfig("My API", "1.0.0"))
+//	humaflow.HandleUnmatched(mux, api)
+func HandleUnmatched(m *flow.Mux, api huma.API) {
+	unmatched := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		huma.HandleUnmatched(api, &goContext{r: r, w: w})
+	})
+	m.NotFound = unmatched
+	m.MethodNotAllowed = unmatched
+}
@@ -136,3 +136,20 @@ func New(r *gin.Engine, config huma.Config) huma.API {
 func NewWithGroup(r *gin.Engine, g *gin.RouterGroup, config huma.Config) huma.API {
 	return huma.NewAPI(config, &ginAdapter{Handler: r, router: g})
 }
+
+// HandleUnmatched sets the engine's no route and no method handlers to write
+// errors using `huma.HandleUnmatched`, replacing any existing ones. This
+// applies to the whole engine, so with `NewWithGroup` only use it if the
+// engine serves nothing but the API.
+//
+//	engine := gin.New()
+//	api := humagin.New(engine, huma.DefaultConfig("My API", "1.0.0"))
+//	humagin.HandleUnmatched(engine, api)
+func HandleUnmatched(r *gin.Engine, api huma.API) {
+	unmatched := func(c *gin.Context) {
+		huma.HandleUnmatched(api, &ginCtx{orig: c})
+	}
+	r.HandleMethodNotAllowed = true
+	r.NoRoute(unmatched)
+	r.NoMethod(unmatched)
+}
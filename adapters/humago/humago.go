@@ -164,3 +164,17 @@ func New(m Mux, config huma.Config) huma.API {
 func NewWithPrefix(m Mux, prefix string, config huma.Config) huma.API {
 	return huma.NewAPI(config, &goAdapter{m, prefix})
 }
+
+// HandleUnmatched registers a catch-all `/` handler on the mux which writes
+// errors using `huma.HandleUnmatched`. Go's mux has no separate not found or
+// method not allowed handlers, so this can't be combined with your own `/`
+// handler, e.g. to serve static files.
+//
+//	mux := http.NewServeMux()
+//	api := humago.New(mux, huma.DefaultConfig("My API", "1.0.0"))
+//	humago.HandleUnmatched(mux, api)
+func HandleUnmatched(m Mux, api huma.API) {
+	m.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		huma.HandleUnmatched(api, &goContext{r: r, w: w})
+	})
+}
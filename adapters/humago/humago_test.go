@@ -177,3 +177,24 @@ func BenchmarkRawGo(b *testing.B) {
 		}
 	}
 }
+
+func TestHandleUnmatched(t *testing.T) {
+	r := http.NewServeMux()
+	app := New(r, huma.DefaultConfig("Test", "1.0.0"))
+	huma.Put(app, "/things/{id}", func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	})
+	HandleUnmatched(r, app)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/things/1", nil))
+	if w.Code != http.StatusMethodNotAllowed || w.Header().Get("Allow") != "PUT" {
+		t.Fatalf("expected 405 with Allow header, got %d %v", w.Code, w.Header())
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/missing", nil))
+	if w.Code != http.StatusNotFound || w.Header().Get("Content-Type") != "application/problem+json" {
+		t.Fatalf("expected 404 problem, got %d %v", w.Code, w.Header())
+	}
+}
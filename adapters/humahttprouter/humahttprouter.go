@@ -128,3 +128,18 @@ func (a *httprouterAdapter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 func New(r *httprouter.Router, config huma.Config) huma.API {
 	return huma.NewAPI(config, &httprouterAdapter{router: r})
 }
+
+// HandleUnmatched sets the router's not found and method not allowed handlers
+// to write errors using `huma.HandleUnmatched`, replacing any existing ones.
+//
+//	router := httprouter.New()
+//	api := humahttprouter.New(router, huma.DefaultConfig("My API", "1.0.0"))
+//	humahttprouter.HandleUnmatched(router, api)
+func HandleUnmatched(r *httprouter.Router, api huma.API) {
+	unmatched := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		huma.HandleUnmatched(api, &httprouterContext{r: r, w: w})
+	})
+	r.NotFound = unmatched
+	r.MethodNotAllowed = unmatched
+	r.HandleMethodNotAllowed = true
+}
@@ -126,3 +126,17 @@ func (a *gMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 func New(r *mux.Router, config huma.Config) huma.API {
 	return huma.NewAPI(config, &gMux{router: r})
 }
+
+// HandleUnmatched sets the router's not found and method not allowed handlers
+// to write errors using `huma.HandleUnmatched`, replacing any existing ones.
+//
+//	router := mux.NewRouter()
+//	api := humamux.New(router, huma.DefaultConfig("My API", "1.0.0"))
+//	humamux.HandleUnmatched(router, api)
+func HandleUnmatched(r *mux.Router, api huma.API) {
+	unmatched := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		huma.HandleUnmatched(api, &gmuxContext{r: r, w: w})
+	})
+	r.NotFoundHandler = unmatched
+	r.MethodNotAllowedHandler = unmatched
+}
@@ -22,6 +22,8 @@ var rxSchema = regexp.MustCompile(`#/components/schemas/([^"]+)`)
 
 var ErrUnknownContentType = errors.New("unknown content type")
 
+var ErrUnknownContentEncoding = errors.New("unknown content encoding")
+
 // Resolver runs a `Resolve` function after a request has been parsed, enabling
 // you to run custom validation or other code that can modify the request and /
 // or return errors.
@@ -181,9 +183,30 @@ type Config struct {
 	// chosen from the keys of `Formats`.
 	DefaultFormat string
 
+	// ContentDecoders defines the supported request body encodings by
+	// `Content-Encoding` value (e.g. `gzip`), which are transparently decoded
+	// before the body is parsed. If set, requests using any other encoding
+	// are rejected with an HTTP 415 response. If unset, request bodies are
+	// passed through as-is. Inputs with only a `RawBody []byte` or
+	// `RawBody io.Reader` always get the body as sent, leaving any decoding
+	// to the handler.
+	ContentDecoders map[string]ContentDecoder
+
 	// Transformers are a way to modify a response body before it is serialized.
 	Transformers []Transformer
 
+	// ValidateResponses enables validation of every operation's output body
+	// and headers against the documented response, calling the given function
+	// with any errors. This is useful during development and testing to catch
+	// drift between handlers and the OpenAPI. Individual operations can
+	// override it via `Operation.ValidateResponses`.
+	ValidateResponses ResponseValidator
+
+	// StrictStatus enables `Operation.StrictStatus` for every operation, so
+	// handlers setting an undocumented output status get an HTTP 500 error.
+	// This is useful during development and testing.
+	StrictStatus bool
+
 	// CreateHooks is a list of functions that will be called before the API is
 	// created. This allows you to modify the configuration at creation time,
 	// for example if you need access to the path settings that may be changed
@@ -244,6 +267,10 @@ type Format struct {
 	Unmarshal func(data []byte, v any) error
 }
 
+// ContentDecoder creates a reader which decodes a request body sent with a
+// specific `Content-Encoding`, e.g. `gzip`. See `DefaultContentDecoders`.
+type ContentDecoder func(r io.Reader) (io.ReadCloser, error)
+
 type api struct {
 	config       Config
 	adapter      Adapter
@@ -280,6 +307,76 @@ func (a *api) Unmarshal(contentType string, data []byte, v any) error {
 	return f.Unmarshal(data, v)
 }
 
+// configOf returns the config of the API, which may be wrapped, e.g. by a
+// group. APIs which were not created by `NewAPI` get an empty config.
+func configOf(api API) Config {
+	oapi := api.OpenAPI()
+	if oapi.config != nil {
+		return *oapi.config
+	}
+	return Config{OpenAPI: oapi}
+}
+
+// decodeContent wraps a request body reader to decode the given
+// `Content-Encoding`, which may be a comma-separated list of encodings in the
+// order they were applied. Returns `ErrUnknownContentEncoding` if an encoding
+// is not supported.
+func decodeContent(decoders map[string]ContentDecoder, contentEncoding string, r io.Reader) (io.ReadCloser, error) {
+	rc := io.NopCloser(r)
+	if decoders == nil {
+		return rc, nil
+	}
+
+	// Encodings are listed in the order they were applied, so decode them in
+	// reverse, e.g. `gzip, br` is decoded as brotli first, then gzip.
+	encodings := strings.Split(contentEncoding, ",")
+	closers := make([]io.Closer, 0, len(encodings))
+	for i := len(encodings) - 1; i >= 0; i-- {
+		enc := strings.ToLower(strings.TrimSpace(encodings[i]))
+		if enc == "" || enc == "identity" {
+			continue
+		}
+		decoder, ok := decoders[enc]
+		if !ok {
+			closeAll(closers)
+			return nil, fmt.Errorf("%w: %s", ErrUnknownContentEncoding, enc)
+		}
+		decoded, err := decoder(rc)
+		if err != nil {
+			closeAll(closers)
+			return nil, err
+		}
+		closers = append(closers, decoded)
+		rc = decoded
+	}
+
+	if len(closers) > 1 {
+		return &multiCloser{Reader: rc, closers: closers}, nil
+	}
+	return rc, nil
+}
+
+// multiCloser closes every decoder in a chain of decoders.
+type multiCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (m *multiCloser) Close() error {
+	return closeAll(m.closers)
+}
+
+// closeAll closes each closer from last to first, returning the first error.
+func closeAll(closers []io.Closer) error {
+	var err error
+	for i := len(closers) - 1; i >= 0; i-- {
+		if cerr := closers[i].Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
 func (a *api) Negotiate(accept string) (string, error) {
 	ct := negotiation.SelectQValueFast(accept, a.formatKeys)
 	if ct == "" && a.formatKeys != nil {
@@ -352,13 +449,6 @@ func NewAPI(config Config, a Adapter) API {
 		config = config.CreateHooks[i](config)
 	}
 
-	newAPI := &api{
-		config:       config,
-		adapter:      a,
-		formats:      map[string]Format{},
-		transformers: config.Transformers,
-	}
-
 	if config.OpenAPI == nil {
 		config.OpenAPI = &OpenAPI{}
 	}
@@ -378,6 +468,15 @@ func NewAPI(config Config, a Adapter) API {
 	if config.DefaultFormat == "" && config.Formats["application/json"].Marshal != nil {
 		config.DefaultFormat = "application/json"
 	}
+
+	newAPI := &api{
+		config:       config,
+		adapter:      a,
+		formats:      map[string]Format{},
+		transformers: config.Transformers,
+	}
+	config.OpenAPI.config = &newAPI.config
+
 	if config.DefaultFormat != "" {
 		newAPI.formatKeys = append(newAPI.formatKeys, config.DefaultFormat)
 	}
@@ -482,8 +581,12 @@ func NewAPI(config Config, a Adapter) API {
 			// Some routers dislike a path param+suffix, so we strip it here instead.
 			schema := strings.TrimSuffix(ctx.Param("schema"), ".json")
 			ctx.SetHeader("Content-Type", "application/json")
+			schemasPath := config.SchemasPath
+			if prefix := getAPIPrefix(newAPI.OpenAPI()); prefix != "" {
+				schemasPath = path.Join(prefix, schemasPath)
+			}
 			b, _ := json.Marshal(config.OpenAPI.Components.Schemas.Map()[schema])
-			b = rxSchema.ReplaceAll(b, []byte(config.SchemasPath+`/$1.json`))
+			b = rxSchema.ReplaceAll(b, []byte(schemasPath+`/$1.json`))
 			ctx.BodyWriter().Write(b)
 		})
 	}
@@ -1,8 +1,12 @@
 package huma_test
 
 import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
 	"context"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/danielgtaylor/huma/v2"
@@ -20,6 +24,58 @@ func TestBlankConfig(t *testing.T) {
 	})
 }
 
+func registerEcho(api huma.API) {
+	huma.Put(api, "/echo", func(ctx context.Context, input *struct {
+		Body struct {
+			Name string `json:"name"`
+		}
+	}) (*struct{ Body string }, error) {
+		return &struct{ Body string }{Body: input.Body.Name}, nil
+	})
+}
+
+func TestDecodeStacked(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+	registerEcho(api)
+
+	// Encode with deflate, then gzip.
+	buf := bytes.Buffer{}
+	gz := gzip.NewWriter(&buf)
+	zw := zlib.NewWriter(gz)
+	zw.Write([]byte(`{"name": "hello"}`))
+	zw.Close()
+	gz.Close()
+
+	resp := api.Put("/echo", "Content-Type: application/json", "Content-Encoding: deflate, GZIP", &buf)
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.Equal(t, `"hello"`, strings.TrimSpace(resp.Body.String()))
+
+	resp = api.Put("/echo", "Content-Type: application/json", "Content-Encoding: gzip, br", strings.NewReader(""))
+	assert.Equal(t, http.StatusUnsupportedMediaType, resp.Code)
+}
+
+func TestDecodeDisabled(t *testing.T) {
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.ContentDecoders = nil
+	api := humatest.Wrap(t, huma.NewAPI(config, humatest.NewAdapter()))
+	registerEcho(api)
+
+	// Without any decoders configured the body is passed through as-is.
+	resp := api.Put("/echo", "Content-Type: application/json", "Content-Encoding: gzip", strings.NewReader(`{"name": "hello"}`))
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+}
+
+func TestDecodersNotShared(t *testing.T) {
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	delete(config.ContentDecoders, "gzip")
+
+	// Other APIs still support gzip.
+	api := humatest.Wrap(t, huma.NewAPI(huma.DefaultConfig("Test API", "1.0.0"), humatest.NewAdapter()))
+	registerEcho(api)
+	resp := api.Put("/echo", "Content-Type: application/json", "Content-Encoding: gzip", strings.NewReader(gzipString(`{"name": "hello"}`)))
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+}
+
 // ExampleAdapter_handle demonstrates how to use the adapter directly
 // instead of using the `huma.Register` convenience function to add a new
 // operation and handler to the API.
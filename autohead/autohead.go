@@ -0,0 +1,178 @@
+// Package autohead provides a way to automatically generate HEAD operations
+// for resources which have a GET but no HEAD. Not all routers answer HEAD
+// requests for GET routes, and even when they do the HEAD operation is not
+// listed in the OpenAPI. Clients use HEAD to check whether a resource exists,
+// whether it has changed via its `ETag` or `Last-Modified` headers, or how
+// large it is before downloading it.
+package autohead
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/casing"
+)
+
+// AutoHead generates HTTP HEAD operations for any resource which has a GET
+// but no pre-existing HEAD operation. Generated HEAD operations will call
+// GET, then send its status code and headers, including the
+// `Content-Length`, without the body. This method may be safely called
+// multiple times.
+//
+// If you wish to disable this for a specific resource, set the `autohead`
+// operation metadata field to `false` on the GET operation and it will be
+// skipped.
+func AutoHead(api huma.API) {
+	oapi := api.OpenAPI()
+	for _, path := range oapi.Paths {
+		if path.Get == nil || path.Head != nil {
+			continue
+		}
+		if path.Get.Metadata != nil {
+			if b, ok := path.Get.Metadata["autohead"].(bool); ok && !b {
+				// Special case: explicitly disabled.
+				continue
+			}
+		}
+		HeadResource(api, path)
+	}
+}
+
+// HeadResource is called for each resource which needs a HEAD operation to
+// be added. It registers and provides a handler for this new operation. You
+// may call this manually if you prefer to not use `AutoHead` for all of your
+// resources and want more fine-grained control.
+func HeadResource(api huma.API, path *huma.PathItem) {
+	oapi := api.OpenAPI()
+	get := path.Get
+
+	// Guess a name for this head operation based on the GET operation.
+	parts := casing.Split(get.OperationID)
+	if len(parts) > 1 && (strings.ToLower(parts[0]) == "get" || strings.ToLower(parts[0]) == "fetch") {
+		parts = parts[1:]
+	}
+	name := casing.Join(parts, "-")
+
+	// The responses are the same as for the GET, but without a body.
+	responses := make(map[string]*huma.Response, len(get.Responses))
+	for k, v := range get.Responses {
+		// Copy the maps so changes to either operation's docs don't leak into
+		// the other one.
+		resp := &huma.Response{
+			Ref:         v.Ref,
+			Description: v.Description,
+		}
+		if v.Headers != nil {
+			resp.Headers = make(map[string]*huma.Param, len(v.Headers))
+			for name, h := range v.Headers {
+				resp.Headers[name] = h
+			}
+		}
+		if v.Links != nil {
+			resp.Links = make(map[string]*huma.Link, len(v.Links))
+			for name, l := range v.Links {
+				resp.Links[name] = l
+			}
+		}
+		if v.Extensions != nil {
+			resp.Extensions = make(map[string]any, len(v.Extensions))
+			for name, e := range v.Extensions {
+				resp.Extensions[name] = e
+			}
+		}
+		responses[k] = resp
+	}
+
+	// Manually register the operation so it shows up in the generated OpenAPI.
+	op := &huma.Operation{
+		OperationID: "head-" + name,
+		Method:      http.MethodHead,
+		Path:        get.Path,
+		Summary:     "Head " + name,
+		Description: "Returns the same status and headers as the GET operation, without the body.",
+		Tags:        get.Tags,
+		Deprecated:  get.Deprecated,
+		Parameters:  get.Parameters,
+		Responses:   responses,
+		Security:    get.Security,
+		Servers:     get.Servers,
+		Hidden:      get.Hidden,
+	}
+	oapi.AddOperation(op)
+
+	// Manually register the handler with the router. This bypasses the normal
+	// Huma API since this is easier and we are just calling the pre-existing
+	// GET operation.
+	adapter := api.Adapter()
+	adapter.Handle(op, func(ctx huma.Context) {
+		// The GET request is canceled along with the HEAD request and comes
+		// from the same client. Values of the HEAD request's context are not
+		// passed on, as some routers store their routing state in it.
+		u := ctx.URL()
+		getReq, err := http.NewRequestWithContext(cancelContext{ctx.Context()}, http.MethodGet, u.String(), nil)
+		if err != nil {
+			huma.WriteErr(api, ctx, http.StatusInternalServerError, "Unable to get resource", err)
+			return
+		}
+		getReq.Host = ctx.Host()
+		getReq.RemoteAddr = ctx.RemoteAddr()
+		ctx.EachHeader(func(k, v string) {
+			getReq.Header.Add(k, v)
+		})
+
+		w := &headWriter{header: http.Header{}}
+		adapter.ServeHTTP(w, getReq)
+
+		for key, values := range w.header {
+			for _, value := range values {
+				ctx.AppendHeader(key, value)
+			}
+		}
+		if w.header.Get("Content-Length") == "" && w.size > 0 {
+			ctx.SetHeader("Content-Length", strconv.FormatInt(w.size, 10))
+		}
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+		ctx.SetStatus(w.status)
+	})
+}
+
+// cancelContext has the deadline and cancellation of its parent context, but
+// none of its values.
+type cancelContext struct {
+	context.Context
+}
+
+func (cancelContext) Value(key any) any {
+	return nil
+}
+
+// headWriter is a response writer which keeps the status code and headers
+// but discards the body, only keeping track of its size.
+type headWriter struct {
+	header http.Header
+	status int
+	size   int64
+}
+
+func (w *headWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *headWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *headWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.size += int64(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,135 @@
+package autohead
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humachi"
+	"github.com/danielgtaylor/huma/v2/humatest"
+)
+
+type ThingModel struct {
+	ID string `json:"id"`
+}
+
+func TestHead(t *testing.T) {
+	// Use a router which does not answer HEAD requests for GET routes itself.
+	api := humatest.Wrap(t, humachi.New(chi.NewMux(), huma.DefaultConfig("Test API", "1.0.0")))
+
+	calls := 0
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-thing",
+		Method:      http.MethodGet,
+		Path:        "/things/{thing-id}",
+		Tags:        []string{"Things"},
+		Errors:      []int{http.StatusNotFound},
+	}, func(ctx context.Context, input *struct {
+		ThingID string `path:"thing-id"`
+		Detail  bool   `query:"detail"`
+	}) (*struct {
+		ETag string `header:"ETag"`
+		Body ThingModel
+	}, error) {
+		calls++
+		if input.ThingID != "test" {
+			return nil, huma.Error404NotFound("Not found")
+		}
+		assert.True(t, input.Detail)
+		return &struct {
+			ETag string `header:"ETag"`
+			Body ThingModel
+		}{ETag: "abc123", Body: ThingModel{ID: input.ThingID}}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-skipped",
+		Method:      http.MethodGet,
+		Path:        "/skipped",
+		Metadata: map[string]any{
+			"autohead": false,
+		},
+	}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	AutoHead(api)
+	AutoHead(api) // Should be a no-op
+
+	head := api.OpenAPI().Paths["/things/{thing-id}"].Head
+	require.NotNil(t, head)
+	assert.Equal(t, "head-thing", head.OperationID)
+	assert.Equal(t, []string{"Things"}, head.Tags)
+	assert.Len(t, head.Parameters, 2)
+	assert.NotNil(t, head.Responses["200"].Headers["ETag"])
+	assert.Empty(t, head.Responses["200"].Content)
+	assert.NotNil(t, head.Responses["404"])
+
+	// The GET's response is untouched.
+	assert.NotEmpty(t, api.OpenAPI().Paths["/things/{thing-id}"].Get.Responses["200"].Content)
+
+	assert.Nil(t, api.OpenAPI().Paths["/skipped"].Head)
+
+	get := api.Get("/things/test?detail=true")
+	resp := api.Do(http.MethodHead, "/things/test?detail=true")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "abc123", resp.Header().Get("ETag"))
+	assert.Equal(t, get.Header().Get("Content-Type"), resp.Header().Get("Content-Type"))
+	assert.Equal(t, strconv.Itoa(get.Body.Len()), resp.Header().Get("Content-Length"))
+	assert.Empty(t, resp.Body.String())
+	assert.Equal(t, 2, calls)
+
+	resp = api.Do(http.MethodHead, "/things/missing?detail=true")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	assert.Equal(t, "application/problem+json", resp.Header().Get("Content-Type"))
+	assert.Empty(t, resp.Body.String())
+}
+
+func TestHeadRequest(t *testing.T) {
+	router := chi.NewMux()
+	api := humachi.New(router, huma.DefaultConfig("Test API", "1.0.0"))
+
+	var remoteAddr, host string
+	var ctxErr error
+	api.UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+		if ctx.Method() == http.MethodGet {
+			remoteAddr = ctx.RemoteAddr()
+			host = ctx.Host()
+			ctxErr = ctx.Context().Err()
+		}
+		next(ctx)
+	})
+	huma.Get(api, "/thing", func(ctx context.Context, input *struct{}) (*struct {
+		ETag string `header:"ETag"`
+	}, error) {
+		return &struct {
+			ETag string `header:"ETag"`
+		}{ETag: "abc"}, nil
+	})
+	AutoHead(api)
+
+	// The HEAD docs can be changed without changing the GET docs.
+	head := api.OpenAPI().Paths["/thing"].Head
+	head.Responses["204"].Headers["X-Head-Only"] = &huma.Param{}
+	assert.NotContains(t, api.OpenAPI().Paths["/thing"].Get.Responses["204"].Headers, "X-Head-Only")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodHead, "http://example.com/thing", nil).WithContext(ctx)
+	req.RemoteAddr = "10.0.0.1:1234"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, "abc", w.Header().Get("ETag"))
+	assert.Equal(t, "10.0.0.1:1234", remoteAddr)
+	assert.Equal(t, "example.com", host)
+	assert.ErrorIs(t, ctxErr, context.Canceled)
+}
@@ -1,5 +1,11 @@
 package huma
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
 // Middlewares is a list of middleware functions that can be attached to an
 // API and will be called for all incoming requests.
 type Middlewares []func(ctx Context, next func(Context))
@@ -32,3 +38,36 @@ func (m Middlewares) chain(endpoint func(Context)) func(Context) {
 	}
 	return w
 }
+
+// RequireNoOperations panics if the API already has operations. Middleware is
+// captured when each operation is registered, so packages which protect or
+// change operations via middleware, like security enforcement, call this to
+// fail loudly instead of silently skipping the existing operations. The
+// `name` is used in the panic message. For a group, only operations within the
+// group's prefix are considered. Hidden operations are not documented in the
+// OpenAPI and so cannot be detected.
+func RequireNoOperations(api API, name string) {
+	prefix := ""
+	if g, ok := api.(interface{ Prefix() string }); ok {
+		prefix = g.Prefix()
+	}
+	paths := make([]string, 0, len(api.OpenAPI().Paths))
+	for path := range api.OpenAPI().Paths {
+		if hasPathPrefix(path, prefix) {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) > 0 {
+		sort.Strings(paths)
+		panic(fmt.Sprintf("%s must be set up before registering operations, found %s", name, strings.Join(paths, ", ")))
+	}
+}
+
+// hasPathPrefix returns whether the path is within the prefix, comparing
+// whole segments so that e.g. `/v10/things` is not within `/v1`.
+func hasPathPrefix(path, prefix string) bool {
+	if !strings.HasPrefix(path, prefix) {
+		return false
+	}
+	return len(path) == len(prefix) || strings.HasSuffix(prefix, "/") || path[len(prefix)] == '/'
+}
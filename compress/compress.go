@@ -0,0 +1,344 @@
+// Package compress provides router-agnostic response compression for Huma
+// APIs. The encoding is negotiated with the client using the
+// `Accept-Encoding` header, small responses and content types which are
+// already compressed are sent as-is, and streaming responses like Server
+// Sent Events are flushed through the compressor.
+//
+//	api := humachi.New(router, huma.DefaultConfig("My API", "1.0.0"))
+//	api.UseMiddleware(compress.New(compress.DefaultConfig()))
+package compress
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/negotiation"
+)
+
+// Writer is a compressing writer which can be reused via `Reset`. The
+// `gzip.Writer`, `zlib.Writer`, and `flate.Writer` types all satisfy it.
+type Writer interface {
+	io.WriteCloser
+
+	// Flush writes any pending compressed data to the underlying writer.
+	Flush() error
+
+	// Reset discards the writer's state and makes it write to `w`.
+	Reset(w io.Writer)
+}
+
+// Encoder describes a supported `Content-Encoding`.
+type Encoder struct {
+	// Name of the encoding as used in the `Accept-Encoding` and
+	// `Content-Encoding` headers, e.g. `gzip`.
+	Name string
+
+	// New creates a new compressing writer which writes to `w`.
+	New func(w io.Writer) Writer
+}
+
+// Gzip is an encoder for the `gzip` content encoding.
+var Gzip = Encoder{
+	Name: "gzip",
+	New: func(w io.Writer) Writer {
+		return gzip.NewWriter(w)
+	},
+}
+
+// Deflate is an encoder for the `deflate` content encoding, which is
+// actually the zlib format, see RFC 9110 section 8.4.1.2.
+var Deflate = Encoder{
+	Name: "deflate",
+	New: func(w io.Writer) Writer {
+		return zlib.NewWriter(w)
+	},
+}
+
+// DefaultSkipContentTypes are content type prefixes which are already
+// compressed, so compressing them again would only waste CPU.
+var DefaultSkipContentTypes = []string{
+	"application/gzip",
+	"application/x-gzip",
+	"application/zip",
+	"application/zstd",
+	"application/x-7z-compressed",
+	"application/x-bzip2",
+	"application/x-rar-compressed",
+	"audio/",
+	"font/woff",
+	"image/avif",
+	"image/gif",
+	"image/jpeg",
+	"image/png",
+	"image/webp",
+	"video/",
+}
+
+// Config describes how responses are compressed.
+type Config struct {
+	// Encoders lists the supported encodings in order of preference, which is
+	// used to break ties when the client accepts several equally.
+	Encoders []Encoder
+
+	// MinSize is the minimum response body size in bytes before it gets
+	// compressed. Smaller bodies are sent uncompressed, as the savings would
+	// not be worth the overhead. Streamed responses which flush before
+	// reaching this size are always compressed.
+	MinSize int
+
+	// SkipContentTypes are response content type prefixes which are never
+	// compressed, e.g. because they are already compressed.
+	SkipContentTypes []string
+}
+
+// DefaultConfig returns a default configuration which supports `gzip` and
+// `deflate` for response bodies of at least 1 KiB.
+func DefaultConfig() Config {
+	return Config{
+		Encoders:         []Encoder{Gzip, Deflate},
+		MinSize:          1024,
+		SkipContentTypes: DefaultSkipContentTypes,
+	}
+}
+
+// New creates a new middleware which compresses responses using the given
+// configuration. Use it via `api.UseMiddleware(...)` for all operations or
+// set it in `huma.Operation.Middlewares` for specific operations.
+func New(config Config) func(ctx huma.Context, next func(huma.Context)) {
+	names := make([]string, len(config.Encoders))
+	pools := make(map[string]*sync.Pool, len(config.Encoders))
+	for i, e := range config.Encoders {
+		names[i] = e.Name
+		newWriter := e.New
+		pools[e.Name] = &sync.Pool{
+			New: func() any {
+				return newWriter(io.Discard)
+			},
+		}
+	}
+
+	return func(ctx huma.Context, next func(huma.Context)) {
+		// The response varies by encoding even when it ends up uncompressed,
+		// e.g. because it is too small, so caches must take it into account.
+		ctx.AppendHeader("Vary", "Accept-Encoding")
+
+		if len(names) == 0 || ctx.Method() == http.MethodHead {
+			next(ctx)
+			return
+		}
+
+		encoding := negotiation.SelectQValueFast(ctx.Header("Accept-Encoding"), names)
+		if encoding == "" {
+			next(ctx)
+			return
+		}
+
+		w := &writer{
+			ctx:      ctx,
+			config:   &config,
+			encoding: encoding,
+			pool:     pools[encoding],
+		}
+		next(&compressContext{humaContext: ctx, w: w})
+		w.Close()
+	}
+}
+
+type (
+	humaContext huma.Context
+
+	// compressContext wraps a `huma.Context` so that headers and the status
+	// code are held back until it is known whether the response will be
+	// compressed.
+	compressContext struct {
+		humaContext
+		w *writer
+	}
+)
+
+func (c *compressContext) SetStatus(code int) {
+	if c.w.started {
+		c.humaContext.SetStatus(code)
+		return
+	}
+	c.w.status = code
+}
+
+func (c *compressContext) Status() int {
+	if c.w.status != 0 {
+		return c.w.status
+	}
+	return c.humaContext.Status()
+}
+
+func (c *compressContext) SetHeader(name, value string) {
+	if !c.w.started && c.w.header(name, value) {
+		return
+	}
+	c.humaContext.SetHeader(name, value)
+}
+
+func (c *compressContext) AppendHeader(name, value string) {
+	if !c.w.started && c.w.header(name, value) {
+		return
+	}
+	c.humaContext.AppendHeader(name, value)
+}
+
+func (c *compressContext) BodyWriter() io.Writer {
+	return c.w
+}
+
+// writer buffers the start of the response body until either `MinSize`
+// bytes have been written, the response is flushed, or the handler is done,
+// then decides whether to compress it.
+type writer struct {
+	ctx      huma.Context
+	config   *Config
+	encoding string
+	pool     *sync.Pool
+
+	// State before the response is started.
+	status          int
+	contentType     string
+	contentEncoding string
+	contentLength   string
+	buf             []byte
+
+	started bool
+	enc     Writer
+}
+
+// header records headers needed to decide whether to compress the response.
+// Returns true if the header should be held back from the response for now.
+func (w *writer) header(name, value string) bool {
+	switch {
+	case strings.EqualFold(name, "Content-Type"):
+		w.contentType = value
+	case strings.EqualFold(name, "Content-Encoding"):
+		w.contentEncoding = value
+	case strings.EqualFold(name, "Content-Length"):
+		// Only valid for the uncompressed body.
+		w.contentLength = value
+		return true
+	}
+	return false
+}
+
+// compressible returns whether the response should be compressed.
+func (w *writer) compressible() bool {
+	if w.contentEncoding != "" && w.contentEncoding != "identity" {
+		// The handler has already encoded the body.
+		return false
+	}
+	switch w.status {
+	case http.StatusNoContent, http.StatusPartialContent, http.StatusNotModified:
+		return false
+	}
+	ct := strings.ToLower(w.contentType)
+	for _, skip := range w.config.SkipContentTypes {
+		if strings.HasPrefix(ct, skip) {
+			return false
+		}
+	}
+	return true
+}
+
+// start sends the held back headers and status code, optionally enabling
+// compression, then writes out any buffered body data.
+func (w *writer) start(compress bool) error {
+	w.started = true
+	if compress && w.compressible() {
+		w.ctx.SetHeader("Content-Encoding", w.encoding)
+		w.enc = w.pool.Get().(Writer)
+		w.enc.Reset(w.ctx.BodyWriter())
+	} else if w.contentLength != "" {
+		w.ctx.SetHeader("Content-Length", w.contentLength)
+	}
+	if w.status != 0 {
+		w.ctx.SetStatus(w.status)
+	}
+	if len(w.buf) > 0 {
+		_, err := w.write(w.buf)
+		w.buf = nil
+		return err
+	}
+	return nil
+}
+
+func (w *writer) write(p []byte) (int, error) {
+	if w.enc != nil {
+		return w.enc.Write(p)
+	}
+	return w.ctx.BodyWriter().Write(p)
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	if w.started {
+		return w.write(p)
+	}
+
+	if len(w.buf)+len(p) < w.config.MinSize {
+		w.buf = append(w.buf, p...)
+		return len(p), nil
+	}
+
+	if err := w.start(true); err != nil {
+		return 0, err
+	}
+	return w.write(p)
+}
+
+// Flush sends any buffered data to the client, compressing it if possible.
+// This enables streaming responses like Server Sent Events.
+func (w *writer) Flush() {
+	if !w.started {
+		w.start(true)
+	}
+	if w.enc != nil {
+		w.enc.Flush()
+	}
+	if f, ok := w.ctx.BodyWriter().(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// SetWriteDeadline passes the write deadline through to the underlying
+// response writer, if supported.
+func (w *writer) SetWriteDeadline(deadline time.Time) error {
+	if d, ok := w.ctx.BodyWriter().(interface{ SetWriteDeadline(time.Time) error }); ok {
+		return d.SetWriteDeadline(deadline)
+	}
+	return http.ErrNotSupported
+}
+
+// Close finishes the response, sending it uncompressed if it never reached
+// `MinSize` bytes.
+func (w *writer) Close() error {
+	if !w.started {
+		if err := w.start(false); err != nil {
+			return err
+		}
+	}
+	if w.enc != nil {
+		err := w.enc.Close()
+		w.enc.Reset(io.Discard)
+		w.pool.Put(w.enc)
+		w.enc = nil
+		return err
+	}
+	return nil
+}
+
+var (
+	_ Writer = (*gzip.Writer)(nil)
+	_ Writer = (*zlib.Writer)(nil)
+	_ Writer = (*flate.Writer)(nil)
+)
@@ -0,0 +1,133 @@
+package compress
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/danielgtaylor/huma/v2/sse"
+)
+
+type TextOutput struct {
+	ContentType string `header:"Content-Type"`
+	Body        []byte
+}
+
+func setup(t *testing.T) humatest.TestAPI {
+	_, api := humatest.New(t)
+	api.UseMiddleware(New(DefaultConfig()))
+
+	huma.Register(api, huma.Operation{
+		Method: http.MethodGet,
+		Path:   "/text/{size}",
+	}, func(ctx context.Context, input *struct {
+		Size        int    `path:"size"`
+		ContentType string `query:"ct" default:"text/plain"`
+	}) (*TextOutput, error) {
+		return &TextOutput{
+			ContentType: input.ContentType,
+			Body:        []byte(strings.Repeat("a", input.Size)),
+		}, nil
+	})
+
+	return api
+}
+
+func TestCompressGzip(t *testing.T) {
+	api := setup(t)
+
+	resp := api.Get("/text/2000", "Accept-Encoding: br, gzip")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "gzip", resp.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", resp.Header().Get("Vary"))
+	assert.Less(t, resp.Body.Len(), 2000)
+
+	r, err := gzip.NewReader(resp.Body)
+	require.NoError(t, err)
+	b, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat("a", 2000), string(b))
+}
+
+func TestCompressDeflatePreferred(t *testing.T) {
+	api := setup(t)
+
+	resp := api.Get("/text/2000", "Accept-Encoding: gzip;q=0.5, deflate")
+	assert.Equal(t, "deflate", resp.Header().Get("Content-Encoding"))
+
+	r, err := zlib.NewReader(resp.Body)
+	require.NoError(t, err)
+	b, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Len(t, b, 2000)
+}
+
+func TestCompressSkipped(t *testing.T) {
+	api := setup(t)
+
+	for _, item := range []struct {
+		name   string
+		path   string
+		header string
+	}{
+		{"small", "/text/10", "Accept-Encoding: gzip"},
+		{"not-accepted", "/text/2000", "Accept-Encoding: br"},
+		{"zero-q", "/text/2000", "Accept-Encoding: gzip;q=0"},
+		{"no-header", "/text/2000", "X-Foo: bar"},
+		{"compressed-type", "/text/2000?ct=image/png", "Accept-Encoding: gzip"},
+	} {
+		t.Run(item.name, func(t *testing.T) {
+			resp := api.Get(item.path, item.header)
+			assert.Equal(t, http.StatusOK, resp.Code)
+			assert.Empty(t, resp.Header().Get("Content-Encoding"))
+			assert.Equal(t, "Accept-Encoding", resp.Header().Get("Vary"))
+			assert.True(t, strings.HasPrefix(resp.Body.String(), "aaaaaaaaaa"))
+		})
+	}
+}
+
+func TestCompressErrorResponse(t *testing.T) {
+	api := setup(t)
+
+	// Errors go through the same context, so the status is still sent.
+	resp := api.Get("/text/bad", "Accept-Encoding: gzip")
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Contains(t, resp.Body.String(), "invalid integer")
+}
+
+func TestCompressSSE(t *testing.T) {
+	_, api := humatest.New(t)
+	api.UseMiddleware(New(DefaultConfig()))
+
+	sse.Register(api, huma.Operation{
+		Method: http.MethodGet,
+		Path:   "/sse",
+	}, map[string]any{
+		"message": "",
+	}, func(ctx context.Context, input *struct{}, send sse.Sender) {
+		// Small messages are still flushed right away, compressed.
+		assert.NoError(t, send.Data("hello"))
+		assert.NoError(t, send.Data("world"))
+	})
+
+	resp := api.Get("/sse", "Accept-Encoding: gzip")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "gzip", resp.Header().Get("Content-Encoding"))
+	assert.Equal(t, "text/event-stream", resp.Header().Get("Content-Type"))
+	assert.True(t, resp.Flushed)
+
+	r, err := gzip.NewReader(resp.Body)
+	require.NoError(t, err)
+	b, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "data: \"hello\"\n\ndata: \"world\"\n\n", string(b))
+}
@@ -0,0 +1,225 @@
+package conditional
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// ETagMetadataKey is the operation metadata key which can be set to `false`
+// to disable automatic ETags for an operation.
+const ETagMetadataKey = "etag"
+
+// ETagConfig describes how ETags are generated.
+type ETagConfig struct {
+	// Weak generates weak ETags like `W/"..."`, which only indicate that the
+	// responses are semantically equivalent. Use this if e.g. the responses
+	// may be compressed differently.
+	Weak bool
+}
+
+// AutoETag enables automatic ETags for `GET` operations. The marshaled
+// response body is hashed to set the `ETag` header, and requests with a
+// matching `If-None-Match` header get a `304 Not Modified` without a body.
+// Handlers can still set their own `ETag` header, which is then used instead
+// of the hash. It panics if operations have already been registered.
+// Responses without a documented `200 OK` body, like `huma.StreamResponse`,
+// server sent events and `RangeOutput` downloads, are skipped and never
+// buffered, as are responses with any other status code.
+//
+//	conditional.AutoETag(api, conditional.ETagConfig{})
+//
+// Operations with automatic ETags document the `If-None-Match` parameter,
+// the `ETag` response header and the `304 Not Modified` response.
+func AutoETag(api huma.API, config ETagConfig) {
+	huma.RequireNoOperations(api, "conditional.AutoETag")
+
+	oapi := api.OpenAPI()
+	oapi.OnAddOperation = append(oapi.OnAddOperation, func(oapi *huma.OpenAPI, op *huma.Operation) {
+		if autoETag(op) {
+			documentETag(op)
+		}
+	})
+
+	api.UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+		if !autoETag(ctx.Operation()) {
+			next(ctx)
+			return
+		}
+
+		ec := &etagContext{humaContext: ctx}
+		next(ec)
+		if ec.passthrough {
+			return
+		}
+
+		status := ec.status
+		if status == 0 && ec.buf.Len() > 0 {
+			status = http.StatusOK
+		}
+		if status != http.StatusOK {
+			if status != 0 {
+				ctx.SetStatus(status)
+			}
+			ctx.BodyWriter().Write(ec.buf.Bytes())
+			return
+		}
+
+		etag := ec.etag
+		if etag == "" {
+			sum := sha256.Sum256(ec.buf.Bytes())
+			etag = `"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+			if config.Weak {
+				etag = "W/" + etag
+			}
+			ctx.SetHeader("ETag", etag)
+		}
+
+		if noneMatch(ctx, etag) {
+			ctx.SetStatus(http.StatusNotModified)
+			return
+		}
+		ctx.SetStatus(status)
+		ctx.BodyWriter().Write(ec.buf.Bytes())
+	})
+}
+
+// autoETag returns whether ETags are generated for the operation.
+func autoETag(op *huma.Operation) bool {
+	if op == nil || op.Method != http.MethodGet {
+		return false
+	}
+	if enabled, ok := op.Metadata[ETagMetadataKey].(bool); ok && !enabled {
+		return false
+	}
+	if op.Responses["206"] != nil {
+		// Partial content is handled by the operation, e.g. via `RangeOutput`.
+		return false
+	}
+	ok := op.Responses["200"]
+	if ok == nil || len(ok.Content) == 0 || ok.Content["text/event-stream"] != nil {
+		// Nothing to hash, or the body is streamed by a `Body func(huma.Context)`
+		// like `huma.StreamResponse`, so it must not be buffered.
+		return false
+	}
+	return true
+}
+
+// noneMatch returns whether the request's `If-None-Match` header matches the
+// ETag, using the weak comparison.
+func noneMatch(ctx huma.Context, etag string) bool {
+	matched := false
+	trimmed := trimETag(etag)
+	ctx.EachHeader(func(name, value string) {
+		if !strings.EqualFold(name, "If-None-Match") {
+			return
+		}
+		for _, match := range strings.Split(value, ",") {
+			match = trimETag(strings.TrimSpace(match))
+			if match == "*" || match == trimmed {
+				matched = true
+			}
+		}
+	})
+	return matched
+}
+
+type (
+	humaContext huma.Context
+
+	// etagContext wraps a `huma.Context` to hold back the status and body
+	// until the ETag is known. Responses with any status other than `200 OK`
+	// are passed through without buffering.
+	etagContext struct {
+		humaContext
+		status      int
+		etag        string
+		buf         bytes.Buffer
+		passthrough bool
+	}
+)
+
+func (c *etagContext) SetStatus(code int) {
+	if code != http.StatusOK && c.buf.Len() == 0 {
+		c.passthrough = true
+		c.humaContext.SetStatus(code)
+		return
+	}
+	c.status = code
+}
+
+func (c *etagContext) Status() int {
+	if c.status != 0 && !c.passthrough {
+		return c.status
+	}
+	return c.humaContext.Status()
+}
+
+func (c *etagContext) SetHeader(name, value string) {
+	if strings.EqualFold(name, "ETag") {
+		c.etag = value
+	}
+	c.humaContext.SetHeader(name, value)
+}
+
+func (c *etagContext) AppendHeader(name, value string) {
+	if strings.EqualFold(name, "ETag") {
+		c.etag = value
+	}
+	c.humaContext.AppendHeader(name, value)
+}
+
+func (c *etagContext) BodyWriter() io.Writer {
+	if c.passthrough {
+		return c.humaContext.BodyWriter()
+	}
+	return &c.buf
+}
+
+// documentETag adds the `If-None-Match` parameter, the `ETag` response header
+// and the `304 Not Modified` response to the operation.
+func documentETag(op *huma.Operation) {
+	found := false
+	for _, p := range op.Parameters {
+		if p.In == "header" && strings.EqualFold(p.Name, "If-None-Match") {
+			found = true
+			break
+		}
+	}
+	if !found {
+		op.Parameters = append(op.Parameters, &huma.Param{
+			Name:        "If-None-Match",
+			In:          "header",
+			Description: "Succeeds if the server's resource matches none of the passed ETags, otherwise a 304 Not Modified is returned.",
+			Schema:      &huma.Schema{Type: "string"},
+		})
+	}
+
+	if op.Responses == nil {
+		op.Responses = map[string]*huma.Response{}
+	}
+	if op.Responses["304"] == nil {
+		op.Responses["304"] = &huma.Response{
+			Description: http.StatusText(http.StatusNotModified),
+		}
+	}
+	for code, resp := range op.Responses {
+		if resp.Ref != "" || (code != "200" && code != "304") {
+			continue
+		}
+		if resp.Headers == nil {
+			resp.Headers = map[string]*huma.Param{}
+		}
+		if resp.Headers["ETag"] == nil {
+			resp.Headers["ETag"] = &huma.Param{
+				Description: "Identifies the version of the resource.",
+				Schema:      &huma.Schema{Type: "string"},
+			}
+		}
+	}
+}
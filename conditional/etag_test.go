@@ -0,0 +1,205 @@
+package conditional
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+)
+
+type ThingOutput struct {
+	ETag string `header:"ETag"`
+	Body struct {
+		Name string `json:"name"`
+	}
+}
+
+func registerThings(api huma.API, name *string) {
+	huma.Get(api, "/things/{id}", func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*ThingOutput, error) {
+		if input.ID == "missing" {
+			return nil, huma.Error404NotFound("thing not found")
+		}
+		resp := &ThingOutput{}
+		if input.ID == "custom" {
+			resp.ETag = `"custom"`
+		}
+		resp.Body.Name = *name
+		return resp, nil
+	})
+}
+
+func TestAutoETag(t *testing.T) {
+	_, api := humatest.New(t)
+	AutoETag(api, ETagConfig{})
+
+	name := "first"
+	registerThings(api, &name)
+
+	resp := api.Get("/things/1")
+	require.Equal(t, http.StatusOK, resp.Code)
+	etag := resp.Header().Get("ETag")
+	assert.True(t, strings.HasPrefix(etag, `"`))
+	assert.Contains(t, resp.Body.String(), "first")
+
+	// The ETag is stable for the same response.
+	assert.Equal(t, etag, api.Get("/things/1").Header().Get("ETag"))
+
+	resp = api.Get("/things/1", "If-None-Match: "+etag)
+	assert.Equal(t, http.StatusNotModified, resp.Code)
+	assert.Equal(t, etag, resp.Header().Get("ETag"))
+	assert.Empty(t, resp.Body.String())
+
+	// Lists and weak comparison are supported.
+	resp = api.Get("/things/1", `If-None-Match: "other", W/`+etag)
+	assert.Equal(t, http.StatusNotModified, resp.Code)
+	assert.Equal(t, http.StatusNotModified, api.Get("/things/1", "If-None-Match: *").Code)
+
+	// Changed responses get a new ETag.
+	name = "second"
+	resp = api.Get("/things/1", "If-None-Match: "+etag)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.NotEqual(t, etag, resp.Header().Get("ETag"))
+	assert.Contains(t, resp.Body.String(), "second")
+
+	// Handlers can set their own ETag.
+	resp = api.Get("/things/custom")
+	assert.Equal(t, `"custom"`, resp.Header().Get("ETag"))
+	assert.Equal(t, http.StatusNotModified, api.Get("/things/custom", `If-None-Match: "custom"`).Code)
+
+	// Errors are not modified.
+	resp = api.Get("/things/missing", "If-None-Match: *")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	assert.Empty(t, resp.Header().Get("ETag"))
+	assert.Contains(t, resp.Body.String(), "thing not found")
+}
+
+func TestAutoETagWeak(t *testing.T) {
+	_, api := humatest.New(t)
+	AutoETag(api, ETagConfig{Weak: true})
+
+	name := "thing"
+	registerThings(api, &name)
+	huma.Get(api, "/disabled", func(ctx context.Context, input *struct{}) (*struct{ Body string }, error) {
+		return &struct{ Body string }{Body: "ok"}, nil
+	}, func(op *huma.Operation) {
+		op.Metadata = map[string]any{ETagMetadataKey: false}
+	})
+
+	resp := api.Get("/things/1")
+	etag := resp.Header().Get("ETag")
+	assert.True(t, strings.HasPrefix(etag, `W/"`))
+	assert.Equal(t, http.StatusNotModified, api.Get("/things/1", "If-None-Match: "+etag).Code)
+
+	resp = api.Get("/disabled", "If-None-Match: *")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Empty(t, resp.Header().Get("ETag"))
+}
+
+func TestAutoETagOpenAPI(t *testing.T) {
+	_, api := humatest.New(t)
+	AutoETag(api, ETagConfig{})
+
+	name := "thing"
+	registerThings(api, &name)
+	huma.Put(api, "/things/{id}", func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	op := api.OpenAPI().Paths["/things/{id}"].Get
+	var param *huma.Param
+	for _, p := range op.Parameters {
+		if p.Name == "If-None-Match" {
+			param = p
+		}
+	}
+	require.NotNil(t, param)
+	assert.Equal(t, "header", param.In)
+	require.Contains(t, op.Responses, "304")
+	assert.Contains(t, op.Responses["304"].Headers, "ETag")
+	assert.Contains(t, op.Responses["200"].Headers, "ETag")
+
+	op = api.OpenAPI().Paths["/things/{id}"].Put
+	assert.NotContains(t, op.Responses, "304")
+}
+
+func TestAutoETagAfterRegisterPanics(t *testing.T) {
+	_, api := humatest.New(t)
+	name := "thing"
+	registerThings(api, &name)
+	assert.Panics(t, func() {
+		AutoETag(api, ETagConfig{})
+	})
+}
+
+func TestAutoETagSkipsStreams(t *testing.T) {
+	_, api := humatest.New(t)
+	AutoETag(api, ETagConfig{})
+
+	huma.Get(api, "/files/{name}", func(ctx context.Context, input *struct {
+		RangeParams
+		Name string `path:"name"`
+	}) (*RangeOutput, error) {
+		return input.ServeContent(Content{
+			Reader: strings.NewReader("hello world"),
+			Size:   11,
+		})
+	})
+	huma.Get(api, "/stream", func(ctx context.Context, input *struct{}) (*huma.StreamResponse, error) {
+		return &huma.StreamResponse{
+			Body: func(ctx huma.Context) {
+				// The body goes straight to the client.
+				_, buffered := ctx.(*etagContext)
+				assert.False(t, buffered)
+				ctx.SetStatus(http.StatusOK)
+				ctx.BodyWriter().Write([]byte("streamed"))
+			},
+		}, nil
+	})
+
+	assert.NotContains(t, api.OpenAPI().Paths["/files/{name}"].Get.Responses, "304")
+	assert.NotContains(t, api.OpenAPI().Paths["/stream"].Get.Responses, "304")
+
+	resp := api.Get("/files/a.txt")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "hello world", resp.Body.String())
+	assert.Empty(t, resp.Header().Get("ETag"))
+
+	resp = api.Get("/files/a.txt", "Range: bytes=0-4", "If-None-Match: *")
+	assert.Equal(t, http.StatusPartialContent, resp.Code)
+	assert.Equal(t, "hello", resp.Body.String())
+
+	resp = api.Get("/stream", "If-None-Match: *")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "streamed", resp.Body.String())
+	assert.Empty(t, resp.Header().Get("ETag"))
+}
+
+func TestAutoETagPassthrough(t *testing.T) {
+	_, api := humatest.New(t)
+	AutoETag(api, ETagConfig{})
+
+	huma.Get(api, "/created", func(ctx context.Context, input *struct{}) (*struct {
+		Status int
+		Body   string
+	}, error) {
+		return &struct {
+			Status int
+			Body   string
+		}{Status: http.StatusAccepted, Body: "later"}, nil
+	})
+
+	resp := api.Get("/created", "If-None-Match: *")
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+	assert.Contains(t, resp.Body.String(), "later")
+	assert.Empty(t, resp.Header().Get("ETag"))
+}
@@ -0,0 +1,292 @@
+package conditional
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+var (
+	errInvalidRange = errors.New("invalid range")
+	errNoOverlap    = errors.New("range does not overlap the content")
+)
+
+// RangeParams allow clients to request only part of a resource using the
+// `Range` header, for example to resume an interrupted download. The
+// `If-Range` header makes the request conditional, so that the full resource
+// is sent if it has changed since the partial download started.
+type RangeParams struct {
+	Range   string `header:"Range" doc:"Requests one or more byte ranges of the resource, e.g. bytes=0-1023."`
+	IfRange string `header:"If-Range" doc:"Only send the requested ranges if the resource still matches the passed ETag or date, otherwise send the full resource."`
+}
+
+// Content describes a resource which can be sent in full or in part.
+type Content struct {
+	// Reader is used to read the content. It is not closed when the response
+	// is done.
+	Reader io.ReadSeeker
+
+	// Size of the content in bytes.
+	Size int64
+
+	// ContentType of the content, defaulting to `application/octet-stream`.
+	ContentType string
+
+	// ETag of the content without quotes, if any. It must be a strong ETag
+	// for `If-Range` to match it.
+	ETag string
+
+	// LastModified is when the content was last modified, if known.
+	LastModified time.Time
+}
+
+// RangeOutput is a response which contains either the full content with a
+// `200 OK` or the requested ranges with a `206 Partial Content` status code.
+// Multiple ranges are sent as a `multipart/byteranges` body.
+type RangeOutput struct {
+	Status        int       `enum:"200,206"`
+	AcceptRanges  string    `header:"Accept-Ranges" doc:"Set to bytes when ranges are supported."`
+	ContentType   string    `header:"Content-Type"`
+	ContentLength int64     `header:"Content-Length"`
+	ContentRange  string    `header:"Content-Range" doc:"The range of the content being sent, for a 206 response with a single range."`
+	ETag          string    `header:"ETag"`
+	LastModified  time.Time `header:"Last-Modified"`
+	Body          func(huma.Context)
+}
+
+// byteRange is a range of bytes from the content.
+type byteRange struct {
+	start, length int64
+}
+
+func (r byteRange) contentRange(size int64) string {
+	return fmt.Sprintf("bytes %d-%d/%d", r.start, r.start+r.length-1, size)
+}
+
+func (r byteRange) header(contentType string, size int64) textproto.MIMEHeader {
+	return textproto.MIMEHeader{
+		"Content-Type":  {contentType},
+		"Content-Range": {r.contentRange(size)},
+	}
+}
+
+// parseRange parses a `Range` header value, e.g. `bytes=0-99,-50`, into
+// ranges of content of the given size. Ranges starting past the end of the
+// content are dropped, and if none are left `errNoOverlap` is returned.
+func parseRange(s string, size int64) ([]byteRange, error) {
+	s = strings.TrimPrefix(s, "bytes=")
+	var ranges []byteRange
+	noOverlap := false
+	for _, ra := range strings.Split(s, ",") {
+		ra = textproto.TrimString(ra)
+		if ra == "" {
+			continue
+		}
+		startStr, endStr, ok := strings.Cut(ra, "-")
+		if !ok {
+			return nil, errInvalidRange
+		}
+		startStr, endStr = textproto.TrimString(startStr), textproto.TrimString(endStr)
+		var r byteRange
+		if startStr == "" {
+			// A suffix range like `-500` for the last 500 bytes.
+			if endStr == "" || endStr[0] == '-' {
+				return nil, errInvalidRange
+			}
+			n, err := strconv.ParseInt(endStr, 10, 64)
+			if err != nil || n < 0 {
+				return nil, errInvalidRange
+			}
+			if n == 0 || size == 0 {
+				noOverlap = true
+				continue
+			}
+			if n > size {
+				n = size
+			}
+			r.start = size - n
+			r.length = n
+		} else {
+			start, err := strconv.ParseInt(startStr, 10, 64)
+			if err != nil || start < 0 {
+				return nil, errInvalidRange
+			}
+			if start >= size {
+				noOverlap = true
+				continue
+			}
+			r.start = start
+			if endStr == "" {
+				// An open range like `500-` to the end of the content.
+				r.length = size - start
+			} else {
+				end, err := strconv.ParseInt(endStr, 10, 64)
+				if err != nil || start > end {
+					return nil, errInvalidRange
+				}
+				if end >= size {
+					end = size - 1
+				}
+				r.length = end - start + 1
+			}
+		}
+		ranges = append(ranges, r)
+	}
+	if len(ranges) == 0 {
+		if noOverlap {
+			return nil, errNoOverlap
+		}
+		return nil, errInvalidRange
+	}
+	return ranges, nil
+}
+
+// rangeMatches returns whether the `If-Range` condition, if any, matches the
+// content. Weak ETags never match.
+func (p *RangeParams) rangeMatches(c Content) bool {
+	if p.IfRange == "" {
+		return true
+	}
+	if strings.HasPrefix(p.IfRange, `"`) {
+		return c.ETag != "" && trimETag(p.IfRange) == c.ETag
+	}
+	if t, err := http.ParseTime(p.IfRange); err == nil {
+		return !c.LastModified.IsZero() && c.LastModified.Truncate(time.Second).Equal(t)
+	}
+	return false
+}
+
+// countingWriter counts the bytes written to it.
+type countingWriter int64
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	*w += countingWriter(len(p))
+	return len(p), nil
+}
+
+// ServeContent returns a response for the content based on the `Range` and
+// `If-Range` headers. Only byte ranges are supported, other units are
+// ignored and the full content is sent. Ranges which cannot be satisfied
+// result in a `416 Range Not Satisfiable` error.
+//
+//	huma.Register(api, huma.Operation{
+//		OperationID: "download",
+//		Method:      http.MethodGet,
+//		Path:        "/files/{name}",
+//		Errors:      []int{http.StatusNotFound, http.StatusRequestedRangeNotSatisfiable},
+//	}, func(ctx context.Context, input *struct {
+//		conditional.Params
+//		conditional.RangeParams
+//		Name string `path:"name"`
+//	}) (*conditional.RangeOutput, error) {
+//		f, info := openFile(input.Name)
+//		if err := input.PreconditionFailed(info.ETag, info.ModTime); err != nil {
+//			return nil, err
+//		}
+//		return input.ServeContent(conditional.Content{
+//			Reader:       f,
+//			Size:         info.Size,
+//			ETag:         info.ETag,
+//			LastModified: info.ModTime,
+//		})
+//	})
+func (p *RangeParams) ServeContent(c Content) (*RangeOutput, error) {
+	contentType := c.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	out := &RangeOutput{
+		Status:        http.StatusOK,
+		AcceptRanges:  "bytes",
+		ContentType:   contentType,
+		ContentLength: c.Size,
+		LastModified:  c.LastModified,
+	}
+	if c.ETag != "" {
+		out.ETag = `"` + c.ETag + `"`
+	}
+
+	ranges := []byteRange{{start: 0, length: c.Size}}
+	if strings.HasPrefix(p.Range, "bytes=") && p.rangeMatches(c) {
+		parsed, err := parseRange(p.Range, c.Size)
+		if err != nil {
+			return nil, huma.ErrorWithHeaders(
+				huma.NewError(http.StatusRequestedRangeNotSatisfiable, http.StatusText(http.StatusRequestedRangeNotSatisfiable), &huma.ErrorDetail{
+					Message:  err.Error(),
+					Location: "headers.Range",
+					Value:    p.Range,
+				}),
+				http.Header{"Content-Range": {"bytes */" + strconv.FormatInt(c.Size, 10)}},
+			)
+		}
+
+		var total int64
+		for _, r := range parsed {
+			total += r.length
+		}
+		if total <= c.Size {
+			// Otherwise, overlapping ranges would send more than the full content
+			// so just send the full content instead.
+			ranges = parsed
+			out.Status = http.StatusPartialContent
+		}
+	}
+
+	if len(ranges) == 1 {
+		r := ranges[0]
+		if out.Status == http.StatusPartialContent {
+			out.ContentRange = r.contentRange(c.Size)
+			out.ContentLength = r.length
+		}
+		if _, err := c.Reader.Seek(r.start, io.SeekStart); err != nil {
+			return nil, huma.Error500InternalServerError("cannot read content", err)
+		}
+		out.Body = func(ctx huma.Context) {
+			ctx.SetStatus(out.Status)
+			io.CopyN(ctx.BodyWriter(), c.Reader, r.length)
+		}
+		return out, nil
+	}
+
+	// Compute the size of the multipart body ahead of time, then write it out
+	// using the same boundary.
+	var count countingWriter
+	mw := multipart.NewWriter(&count)
+	for _, r := range ranges {
+		mw.CreatePart(r.header(contentType, c.Size))
+		count += countingWriter(r.length)
+	}
+	mw.Close()
+	boundary := mw.Boundary()
+
+	out.ContentType = "multipart/byteranges; boundary=" + boundary
+	out.ContentLength = int64(count)
+	out.Body = func(ctx huma.Context) {
+		ctx.SetStatus(out.Status)
+		mw := multipart.NewWriter(ctx.BodyWriter())
+		mw.SetBoundary(boundary)
+		for _, r := range ranges {
+			part, err := mw.CreatePart(r.header(contentType, c.Size))
+			if err != nil {
+				return
+			}
+			if _, err := c.Reader.Seek(r.start, io.SeekStart); err != nil {
+				return
+			}
+			if _, err := io.CopyN(part, c.Reader, r.length); err != nil {
+				return
+			}
+		}
+		mw.Close()
+	}
+	return out, nil
+}
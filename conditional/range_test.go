@@ -0,0 +1,196 @@
+package conditional
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+)
+
+func TestParseRange(t *testing.T) {
+	for _, item := range []struct {
+		header string
+		ranges []byteRange
+		err    error
+	}{
+		{"bytes=0-4", []byteRange{{0, 5}}, nil},
+		{"bytes=5-", []byteRange{{5, 5}}, nil},
+		{"bytes=-3", []byteRange{{7, 3}}, nil},
+		{"bytes=-30", []byteRange{{0, 10}}, nil},
+		{"bytes=8-20", []byteRange{{8, 2}}, nil},
+		{"bytes=0-1, 4-5", []byteRange{{0, 2}, {4, 2}}, nil},
+		{"bytes=0-1,20-30", []byteRange{{0, 2}}, nil},
+		{"bytes=20-30", nil, errNoOverlap},
+		{"bytes=-0", nil, errNoOverlap},
+		{"bytes=", nil, errInvalidRange},
+		{"bytes=5-1", nil, errInvalidRange},
+		{"bytes=a-b", nil, errInvalidRange},
+		{"bytes=1", nil, errInvalidRange},
+		{"bytes=--1", nil, errInvalidRange},
+	} {
+		t.Run(item.header, func(t *testing.T) {
+			ranges, err := parseRange(item.header, 10)
+			assert.Equal(t, item.err, err)
+			assert.Equal(t, item.ranges, ranges)
+		})
+	}
+}
+
+func TestServeContent(t *testing.T) {
+	_, api := humatest.New(t)
+
+	modified := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "download",
+		Method:      http.MethodGet,
+		Path:        "/download",
+		Errors:      []int{http.StatusRequestedRangeNotSatisfiable},
+	}, func(ctx context.Context, input *struct {
+		Params
+		RangeParams
+	}) (*RangeOutput, error) {
+		if err := input.PreconditionFailed("abc123", modified); err != nil {
+			return nil, err
+		}
+		return input.ServeContent(Content{
+			Reader:       strings.NewReader("0123456789"),
+			Size:         10,
+			ContentType:  "text/plain",
+			ETag:         "abc123",
+			LastModified: modified,
+		})
+	})
+
+	// Both statuses are documented.
+	responses := api.OpenAPI().Paths["/download"].Get.Responses
+	assert.NotNil(t, responses["200"])
+	assert.NotNil(t, responses["206"])
+	assert.NotNil(t, responses["206"].Headers["Content-Range"])
+	assert.NotNil(t, responses["416"])
+
+	t.Run("full", func(t *testing.T) {
+		resp := api.Get("/download")
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, "bytes", resp.Header().Get("Accept-Ranges"))
+		assert.Equal(t, "10", resp.Header().Get("Content-Length"))
+		assert.Equal(t, `"abc123"`, resp.Header().Get("ETag"))
+		assert.Equal(t, modified.Format(http.TimeFormat), resp.Header().Get("Last-Modified"))
+		assert.Equal(t, "0123456789", resp.Body.String())
+	})
+
+	t.Run("single", func(t *testing.T) {
+		resp := api.Get("/download", "Range: bytes=2-4")
+		assert.Equal(t, http.StatusPartialContent, resp.Code)
+		assert.Equal(t, "bytes 2-4/10", resp.Header().Get("Content-Range"))
+		assert.Equal(t, "3", resp.Header().Get("Content-Length"))
+		assert.Equal(t, "text/plain", resp.Header().Get("Content-Type"))
+		assert.Equal(t, "234", resp.Body.String())
+	})
+
+	t.Run("multiple", func(t *testing.T) {
+		resp := api.Get("/download", "Range: bytes=0-1,-2")
+		assert.Equal(t, http.StatusPartialContent, resp.Code)
+		assert.Empty(t, resp.Header().Get("Content-Range"))
+		assert.Equal(t, strconv.Itoa(resp.Body.Len()), resp.Header().Get("Content-Length"))
+
+		mediaType, params, err := mime.ParseMediaType(resp.Header().Get("Content-Type"))
+		require.NoError(t, err)
+		assert.Equal(t, "multipart/byteranges", mediaType)
+
+		mr := multipart.NewReader(resp.Body, params["boundary"])
+		expected := []struct{ contentRange, body string }{
+			{"bytes 0-1/10", "01"},
+			{"bytes 8-9/10", "89"},
+		}
+		for _, e := range expected {
+			part, err := mr.NextPart()
+			require.NoError(t, err)
+			assert.Equal(t, "text/plain", part.Header.Get("Content-Type"))
+			assert.Equal(t, e.contentRange, part.Header.Get("Content-Range"))
+			b, err := io.ReadAll(part)
+			require.NoError(t, err)
+			assert.Equal(t, e.body, string(b))
+		}
+		_, err = mr.NextPart()
+		assert.Equal(t, io.EOF, err)
+	})
+
+	t.Run("overlapping", func(t *testing.T) {
+		resp := api.Get("/download", "Range: bytes=0-8,1-9")
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, "0123456789", resp.Body.String())
+	})
+
+	t.Run("other-unit", func(t *testing.T) {
+		resp := api.Get("/download", "Range: items=0-1")
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, "0123456789", resp.Body.String())
+	})
+
+	t.Run("not-satisfiable", func(t *testing.T) {
+		resp := api.Get("/download", "Range: bytes=20-30")
+		assert.Equal(t, http.StatusRequestedRangeNotSatisfiable, resp.Code)
+		assert.Equal(t, "bytes */10", resp.Header().Get("Content-Range"))
+	})
+
+	t.Run("if-range-etag", func(t *testing.T) {
+		resp := api.Get("/download", "Range: bytes=0-0", `If-Range: "abc123"`)
+		assert.Equal(t, http.StatusPartialContent, resp.Code)
+		assert.Equal(t, "0", resp.Body.String())
+
+		resp = api.Get("/download", "Range: bytes=0-0", `If-Range: "changed"`)
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, "0123456789", resp.Body.String())
+
+		resp = api.Get("/download", "Range: bytes=0-0", `If-Range: W/"abc123"`)
+		assert.Equal(t, http.StatusOK, resp.Code)
+	})
+
+	t.Run("if-range-date", func(t *testing.T) {
+		resp := api.Get("/download", "Range: bytes=0-0", "If-Range: "+modified.Format(http.TimeFormat))
+		assert.Equal(t, http.StatusPartialContent, resp.Code)
+
+		resp = api.Get("/download", "Range: bytes=0-0", "If-Range: "+modified.Add(-time.Hour).Format(http.TimeFormat))
+		assert.Equal(t, http.StatusOK, resp.Code)
+	})
+
+	t.Run("not-modified", func(t *testing.T) {
+		resp := api.Get("/download", "Range: bytes=0-0", `If-None-Match: "abc123"`)
+		assert.Equal(t, http.StatusNotModified, resp.Code)
+	})
+}
+
+func TestServeContentUnknownModified(t *testing.T) {
+	_, api := humatest.New(t)
+
+	huma.Get(api, "/download", func(ctx context.Context, input *struct {
+		RangeParams
+	}) (*RangeOutput, error) {
+		return input.ServeContent(Content{
+			Reader: strings.NewReader("0123456789"),
+			Size:   10,
+		})
+	})
+
+	resp := api.Get("/download")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.NotContains(t, resp.Header(), "Last-Modified")
+	assert.NotContains(t, resp.Header(), "Etag")
+
+	// A date never matches unknown content, so the full content is sent.
+	resp = api.Get("/download", "Range: bytes=0-0", "If-Range: "+time.Time{}.Format(http.TimeFormat))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "0123456789", resp.Body.String())
+}
@@ -0,0 +1,293 @@
+// Package cors provides router-agnostic Cross-Origin Resource Sharing (CORS)
+// support for Huma APIs. Preflight `OPTIONS` requests are answered using the
+// operations registered for each path, so the allowed methods and headers
+// always match the API, and response headers declared on output structs are
+// exposed to browsers.
+//
+//	api := humachi.New(router, huma.DefaultConfig("My API", "1.0.0"))
+//	cors.Enable(api, cors.Config{
+//		AllowOrigins: []string{"https://example.com"},
+//	})
+//
+//	// Register operations *after* enabling CORS.
+//	huma.Register(api, ...)
+package cors
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// safelistedHeaders are response headers which browsers always expose, so
+// they don't need to be listed in `Access-Control-Expose-Headers`.
+var safelistedHeaders = map[string]bool{
+	"Cache-Control":    true,
+	"Content-Language": true,
+	"Content-Length":   true,
+	"Content-Type":     true,
+	"Expires":          true,
+	"Last-Modified":    true,
+	"Pragma":           true,
+}
+
+// Config describes which cross-origin requests are allowed.
+type Config struct {
+	// AllowOrigins lists the origins which may make requests, e.g.
+	// `https://example.com`. The special value `*` allows any origin.
+	AllowOrigins []string
+
+	// AllowOriginFunc optionally allows origins which are not listed in
+	// `AllowOrigins`, e.g. to allow any subdomain.
+	AllowOriginFunc func(origin string) bool
+
+	// AllowHeaders lists extra request headers which clients may send, in
+	// addition to the header parameters, security scheme headers and
+	// `Content-Type` of the registered operations. The special value `*`
+	// allows any header.
+	AllowHeaders []string
+
+	// ExposeHeaders lists extra response headers which browsers may read, in
+	// addition to the response headers declared by each operation.
+	ExposeHeaders []string
+
+	// AllowCredentials allows requests to include credentials like cookies.
+	// The request's origin is always sent back rather than `*` when set.
+	AllowCredentials bool
+
+	// MaxAge is how long browsers may cache the result of a preflight request.
+	// Zero means the header is not sent.
+	MaxAge time.Duration
+}
+
+// allowOrigin returns the value of the `Access-Control-Allow-Origin` header
+// for the origin, or an empty string if the origin is not allowed.
+func (c *Config) allowOrigin(origin string) string {
+	for _, o := range c.AllowOrigins {
+		if o == "*" {
+			if c.AllowCredentials {
+				return origin
+			}
+			return "*"
+		}
+		if strings.EqualFold(o, origin) {
+			return origin
+		}
+	}
+	if c.AllowOriginFunc != nil && c.AllowOriginFunc(origin) {
+		return origin
+	}
+	return ""
+}
+
+// Enable CORS for the API. Preflight handlers are registered for each path as
+// operations are added to the OpenAPI, and a middleware adds CORS headers to
+// responses. It panics if operations have already been registered.
+//
+// Your own `OPTIONS` operation for a path is used instead of the preflight
+// handler, but it must be registered before any other operation on the same
+// path, otherwise registering it panics.
+func Enable(api huma.API, config Config) {
+	huma.RequireNoOperations(api, "cors.Enable")
+
+	oapi := api.OpenAPI()
+	c := &config
+
+	// Preflight requests are answered by a handler for each registered path.
+	// Operations added later to the same path are picked up at request time.
+	registered := map[string]bool{}
+	register := func(oapi *huma.OpenAPI, op *huma.Operation) {
+		if registered[op.Path] {
+			if op.Method == http.MethodOptions {
+				// The router already has the preflight handler for this path, so
+				// the operation would be a duplicate route.
+				panic(fmt.Sprintf("cors: OPTIONS %s must be registered before any other operation on the path", op.Path))
+			}
+			return
+		}
+		registered[op.Path] = true
+		if item := oapi.Paths[op.Path]; item != nil && item.Options != nil {
+			// The API handles `OPTIONS` for this path itself.
+			return
+		}
+		path := op.Path
+		api.Adapter().Handle(&huma.Operation{
+			OperationID: "cors-preflight",
+			Method:      http.MethodOptions,
+			Path:        path,
+		}, func(ctx huma.Context) {
+			preflight(c, oapi, oapi.Paths[path], ctx)
+		})
+	}
+	oapi.OnAddOperation = append(oapi.OnAddOperation, register)
+
+	// Actual requests get the allowed origin and exposed headers added.
+	var exposed sync.Map
+	api.UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+		origin := ctx.Header("Origin")
+		ctx.AppendHeader("Vary", "Origin")
+		if origin == "" {
+			next(ctx)
+			return
+		}
+		allow := c.allowOrigin(origin)
+		if allow == "" {
+			next(ctx)
+			return
+		}
+		ctx.SetHeader("Access-Control-Allow-Origin", allow)
+		if c.AllowCredentials {
+			ctx.SetHeader("Access-Control-Allow-Credentials", "true")
+		}
+		op := ctx.Operation()
+		headers, ok := exposed.Load(op)
+		if !ok {
+			headers, _ = exposed.LoadOrStore(op, exposeHeaders(c, op))
+		}
+		if h := headers.(string); h != "" {
+			ctx.SetHeader("Access-Control-Expose-Headers", h)
+		}
+		next(ctx)
+	})
+}
+
+// operations returns the operations of a path in a stable order.
+func operations(item *huma.PathItem) []*huma.Operation {
+	ops := []*huma.Operation{}
+	for _, op := range []*huma.Operation{item.Get, item.Head, item.Post, item.Put, item.Patch, item.Delete, item.Options, item.Trace} {
+		if op != nil {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+// preflight answers an `OPTIONS` request for the path, which is either a
+// CORS preflight request or a plain request for the allowed methods.
+func preflight(c *Config, oapi *huma.OpenAPI, item *huma.PathItem, ctx huma.Context) {
+	methods := []string{}
+	headers := map[string]string{}
+	for _, op := range operations(item) {
+		methods = append(methods, op.Method)
+		for _, p := range op.Parameters {
+			if p.In == "header" {
+				headers[http.CanonicalHeaderKey(p.Name)] = p.Name
+			}
+		}
+		if op.RequestBody != nil {
+			headers["Content-Type"] = "Content-Type"
+		}
+		securityHeaders(oapi, op, headers)
+	}
+	methods = append(methods, http.MethodOptions)
+	allowMethods := strings.Join(methods, ", ")
+	ctx.SetHeader("Allow", allowMethods)
+
+	origin := ctx.Header("Origin")
+	if origin != "" && ctx.Header("Access-Control-Request-Method") != "" {
+		ctx.AppendHeader("Vary", "Origin")
+		ctx.AppendHeader("Vary", "Access-Control-Request-Method")
+		ctx.AppendHeader("Vary", "Access-Control-Request-Headers")
+		if allow := c.allowOrigin(origin); allow != "" {
+			ctx.SetHeader("Access-Control-Allow-Origin", allow)
+			ctx.SetHeader("Access-Control-Allow-Methods", allowMethods)
+			if c.AllowCredentials {
+				ctx.SetHeader("Access-Control-Allow-Credentials", "true")
+			}
+			if allowHeaders := allowHeaders(c, headers, ctx.Header("Access-Control-Request-Headers")); allowHeaders != "" {
+				ctx.SetHeader("Access-Control-Allow-Headers", allowHeaders)
+			}
+			if c.MaxAge > 0 {
+				ctx.SetHeader("Access-Control-Max-Age", strconv.Itoa(int(c.MaxAge.Seconds())))
+			}
+		}
+	}
+	ctx.SetStatus(http.StatusNoContent)
+}
+
+// securityHeaders adds the request headers used by the operation's security
+// schemes, e.g. `Authorization` for bearer tokens, to `headers`.
+func securityHeaders(oapi *huma.OpenAPI, op *huma.Operation, headers map[string]string) {
+	if oapi.Components == nil {
+		return
+	}
+	security := op.Security
+	if security == nil {
+		// Operations without their own requirements use the API's.
+		security = oapi.Security
+	}
+	for _, requirement := range security {
+		for name := range requirement {
+			scheme := oapi.Components.SecuritySchemes[name]
+			if scheme == nil {
+				continue
+			}
+			switch scheme.Type {
+			case "http", "oauth2", "openIdConnect":
+				headers["Authorization"] = "Authorization"
+			case "apiKey":
+				if scheme.In == "header" {
+					headers[http.CanonicalHeaderKey(scheme.Name)] = scheme.Name
+				}
+			}
+		}
+	}
+}
+
+// allowHeaders returns the value of the `Access-Control-Allow-Headers` header
+// given the headers declared by the path's operations.
+func allowHeaders(c *Config, declared map[string]string, requested string) string {
+	for _, h := range c.AllowHeaders {
+		if h == "*" {
+			// Allow whatever the client asked for.
+			return requested
+		}
+		declared[http.CanonicalHeaderKey(h)] = h
+	}
+	keys := make([]string, 0, len(declared))
+	for k := range declared {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = declared[k]
+	}
+	return strings.Join(names, ", ")
+}
+
+// exposeHeaders returns the value of the `Access-Control-Expose-Headers`
+// header for an operation.
+func exposeHeaders(c *Config, op *huma.Operation) string {
+	seen := map[string]bool{}
+	names := []string{}
+	add := func(name string) {
+		key := http.CanonicalHeaderKey(name)
+		if !seen[key] && !safelistedHeaders[key] {
+			seen[key] = true
+			names = append(names, name)
+		}
+	}
+	for _, h := range c.ExposeHeaders {
+		add(h)
+	}
+	declared := []string{}
+	if op != nil {
+		for _, resp := range op.Responses {
+			for name := range resp.Headers {
+				declared = append(declared, name)
+			}
+		}
+	}
+	sort.Strings(declared)
+	for _, h := range declared {
+		add(h)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,242 @@
+package cors
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humachi"
+	"github.com/danielgtaylor/huma/v2/humatest"
+)
+
+type ThingOutput struct {
+	ETag      string `header:"ETag"`
+	RequestID string `header:"X-Request-ID"`
+	Body      struct {
+		ID string `json:"id"`
+	}
+}
+
+func register(api huma.API) {
+	huma.Register(api, huma.Operation{
+		OperationID: "get-thing",
+		Method:      http.MethodGet,
+		Path:        "/things/{id}",
+	}, func(ctx context.Context, input *struct {
+		ID      string `path:"id"`
+		TraceID string `header:"X-Trace-ID"`
+	}) (*ThingOutput, error) {
+		if input.ID == "missing" {
+			return nil, huma.Error404NotFound("not found")
+		}
+		resp := &ThingOutput{ETag: "abc", RequestID: "123"}
+		resp.Body.ID = input.ID
+		return resp, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "put-thing",
+		Method:      http.MethodPut,
+		Path:        "/things/{id}",
+	}, func(ctx context.Context, input *struct {
+		ID   string `path:"id"`
+		Body struct {
+			ID string `json:"id"`
+		}
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+}
+
+func TestPreflight(t *testing.T) {
+	_, api := humatest.New(t)
+	Enable(api, Config{
+		AllowOrigins:     []string{"https://example.com"},
+		AllowHeaders:     []string{"Authorization"},
+		AllowCredentials: true,
+		MaxAge:           time.Hour,
+	})
+	register(api)
+
+	resp := api.Do(http.MethodOptions, "/things/123",
+		"Origin: https://example.com",
+		"Access-Control-Request-Method: PUT",
+		"Access-Control-Request-Headers: content-type",
+	)
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Equal(t, "https://example.com", resp.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "GET, PUT, OPTIONS", resp.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "GET, PUT, OPTIONS", resp.Header().Get("Allow"))
+	assert.Equal(t, "Authorization, Content-Type, X-Trace-ID", resp.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "true", resp.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "3600", resp.Header().Get("Access-Control-Max-Age"))
+	assert.Contains(t, resp.Header().Values("Vary"), "Origin")
+
+	// Disallowed origin gets no CORS headers.
+	resp = api.Do(http.MethodOptions, "/things/123",
+		"Origin: https://evil.com",
+		"Access-Control-Request-Method: PUT",
+	)
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Empty(t, resp.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, resp.Header().Get("Access-Control-Allow-Methods"))
+
+	// Plain OPTIONS requests just get the allowed methods.
+	resp = api.Do(http.MethodOptions, "/things/123")
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Equal(t, "GET, PUT, OPTIONS", resp.Header().Get("Allow"))
+	assert.Empty(t, resp.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestActualRequest(t *testing.T) {
+	_, api := humatest.New(t)
+	Enable(api, Config{
+		AllowOrigins:  []string{"*"},
+		ExposeHeaders: []string{"X-Custom"},
+	})
+	register(api)
+
+	resp := api.Get("/things/123", "Origin: https://example.com")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "*", resp.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, resp.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "X-Custom, ETag, X-Request-ID", resp.Header().Get("Access-Control-Expose-Headers"))
+
+	// Errors also get CORS headers so browsers can read them.
+	resp = api.Get("/things/missing", "Origin: https://example.com")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	assert.Equal(t, "*", resp.Header().Get("Access-Control-Allow-Origin"))
+
+	// Same-origin requests are unaffected.
+	resp = api.Get("/things/123")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Empty(t, resp.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "Origin", resp.Header().Get("Vary"))
+}
+
+func TestAllowOriginFunc(t *testing.T) {
+	_, api := humatest.New(t)
+	Enable(api, Config{
+		AllowOriginFunc: func(origin string) bool {
+			return origin == "https://sub.example.com"
+		},
+		AllowHeaders: []string{"*"},
+	})
+	register(api)
+
+	resp := api.Do(http.MethodOptions, "/things/123",
+		"Origin: https://sub.example.com",
+		"Access-Control-Request-Method: GET",
+		"Access-Control-Request-Headers: x-foo, x-bar",
+	)
+	assert.Equal(t, "https://sub.example.com", resp.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "x-foo, x-bar", resp.Header().Get("Access-Control-Allow-Headers"))
+}
+
+func TestAdapters(t *testing.T) {
+	// Works the same with routers which do not handle `OPTIONS` themselves.
+	api := humatest.Wrap(t, humachi.New(chi.NewMux(), huma.DefaultConfig("Test API", "1.0.0")))
+	Enable(api, Config{AllowOrigins: []string{"*"}})
+	register(api)
+
+	resp := api.Do(http.MethodOptions, "/things/123",
+		"Origin: https://example.com",
+		"Access-Control-Request-Method: PUT",
+	)
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Equal(t, "*", resp.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "GET, PUT, OPTIONS", resp.Header().Get("Access-Control-Allow-Methods"))
+}
+
+func TestEnableAfterRegisterPanics(t *testing.T) {
+	_, api := humatest.New(t)
+	register(api)
+	assert.Panics(t, func() {
+		Enable(api, Config{AllowOrigins: []string{"*"}})
+	})
+}
+
+func TestCustomOptions(t *testing.T) {
+	_, api := humatest.New(t)
+	Enable(api, Config{AllowOrigins: []string{"*"}})
+
+	// Registered first, so it's used instead of the preflight handler.
+	huma.Register(api, huma.Operation{
+		OperationID: "options-thing",
+		Method:      http.MethodOptions,
+		Path:        "/things/{id}",
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*struct {
+		Allow string `header:"Allow"`
+	}, error) {
+		return &struct {
+			Allow string `header:"Allow"`
+		}{Allow: "custom"}, nil
+	})
+	register(api)
+
+	resp := api.Do(http.MethodOptions, "/things/123")
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Equal(t, "custom", resp.Header().Get("Allow"))
+
+	// Registered after the preflight handler, so it would be a duplicate route.
+	assert.PanicsWithValue(t, "cors: OPTIONS /other/{id} must be registered before any other operation on the path", func() {
+		huma.Get(api, "/other/{id}", func(ctx context.Context, input *struct {
+			ID string `path:"id"`
+		}) (*struct{}, error) {
+			return nil, nil
+		})
+		huma.Register(api, huma.Operation{
+			OperationID: "options-other",
+			Method:      http.MethodOptions,
+			Path:        "/other/{id}",
+		}, func(ctx context.Context, input *struct {
+			ID string `path:"id"`
+		}) (*struct{}, error) {
+			return nil, nil
+		})
+	})
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.Components.SecuritySchemes = map[string]*huma.SecurityScheme{
+		"bearer": {Type: "http", Scheme: "bearer"},
+		"key":    {Type: "apiKey", In: "header", Name: "X-API-Key"},
+		"query":  {Type: "apiKey", In: "query", Name: "api_key"},
+	}
+	config.Security = []map[string][]string{{"key": {}}}
+	_, api := humatest.New(t, config)
+	Enable(api, Config{AllowOrigins: []string{"*"}})
+
+	handler := func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	}
+	huma.Register(api, huma.Operation{
+		OperationID: "get-secure",
+		Method:      http.MethodGet,
+		Path:        "/secure",
+		Security:    []map[string][]string{{"bearer": {}}, {"query": {}}},
+	}, handler)
+	huma.Get(api, "/default", handler)
+
+	// Headers used by the operation's security schemes are allowed.
+	resp := api.Do(http.MethodOptions, "/secure",
+		"Origin: https://example.com",
+		"Access-Control-Request-Method: GET",
+	)
+	assert.Equal(t, "Authorization", resp.Header().Get("Access-Control-Allow-Headers"))
+
+	// Operations without their own requirements use the API's.
+	resp = api.Do(http.MethodOptions, "/default",
+		"Origin: https://example.com",
+		"Access-Control-Request-Method: GET",
+	)
+	assert.Equal(t, "X-API-Key", resp.Header().Get("Access-Control-Allow-Headers"))
+}
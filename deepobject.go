@@ -0,0 +1,189 @@
+package huma
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// styleDeepObject is the OpenAPI param style used for map and struct query
+// params, e.g. `filter[status]=open&filter[owner]=bob`.
+const styleDeepObject = "deepObject"
+
+// parseDeepObjectQuery parses query params using the OpenAPI `deepObject`
+// style, e.g. `filter[status]=open&filter[owner][name]=bob`, into a nested
+// map of values. Repeated keys result in a `[]string` value. Returns `nil`
+// if no matching keys were found, or an error if a key is used both for a
+// value and an object, e.g. `filter[a]=1&filter[a][b]=2`.
+func parseDeepObjectQuery(query url.Values, name string) (map[string]any, error) {
+	var result map[string]any
+	prefix := name + "["
+
+	// Go through the keys in order so the result doesn't depend on the
+	// random map iteration order.
+	names := make([]string, 0, len(query))
+	for k := range query {
+		if strings.HasPrefix(k, prefix) {
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
+
+	for _, k := range names {
+		values := query[k]
+		if len(values) == 0 {
+			continue
+		}
+
+		// Split `name[a][b]` into its path components `a` and `b`.
+		keys := []string{}
+		rest := k[len(name):]
+		for len(rest) > 0 && rest[0] == '[' {
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				break
+			}
+			keys = append(keys, rest[1:end])
+			rest = rest[end+1:]
+		}
+		if len(keys) == 0 || rest != "" {
+			// Not a valid deep object key, e.g. `name[a` or `name[a]b`.
+			continue
+		}
+
+		if result == nil {
+			result = map[string]any{}
+		}
+		current := result
+		for _, key := range keys[:len(keys)-1] {
+			existing, found := current[key]
+			next, ok := existing.(map[string]any)
+			if !ok {
+				if found {
+					return nil, fmt.Errorf("conflicting keys: %s is also used for a value", k)
+				}
+				next = map[string]any{}
+				current[key] = next
+			}
+			current = next
+		}
+
+		leaf := keys[len(keys)-1]
+		if _, found := current[leaf]; found {
+			return nil, fmt.Errorf("conflicting keys: %s is also used for an object", k)
+		}
+		var value any = values[0]
+		if len(values) > 1 {
+			value = values
+		}
+		current[leaf] = value
+	}
+	return result, nil
+}
+
+// coerceDeepObject converts the string values from a parsed deep object into
+// the types described by the schema, so that they can be validated and then
+// set on the destination field. Values which cannot be converted are left
+// as-is for validation to report.
+func coerceDeepObject(r Registry, s *Schema, v any) any {
+	for s != nil && s.Ref != "" {
+		s = r.SchemaFromRef(s.Ref)
+	}
+
+	switch value := v.(type) {
+	case map[string]any:
+		for k, item := range value {
+			var ps *Schema
+			if s != nil {
+				ps = s.Properties[k]
+				if ps == nil {
+					ps, _ = s.AdditionalProperties.(*Schema)
+				}
+			}
+			value[k] = coerceDeepObject(r, ps, item)
+		}
+		return value
+	case []string:
+		if s != nil && s.Type == TypeArray {
+			items := make([]any, len(value))
+			for i, item := range value {
+				items[i] = coerceDeepObject(r, s.Items, item)
+			}
+			return items
+		}
+		// Not an array, so the first value wins like for other params.
+		return coerceDeepObject(r, s, value[0])
+	case string:
+		if s == nil {
+			return value
+		}
+		switch s.Type {
+		case TypeBoolean:
+			if b, err := strconv.ParseBool(value); err == nil {
+				return b
+			}
+		case TypeInteger:
+			if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+				return i
+			}
+		case TypeNumber:
+			if f, err := strconv.ParseFloat(value, 64); err == nil {
+				return f
+			}
+		case TypeArray:
+			return coerceDeepObject(r, s, strings.Split(value, ","))
+		}
+	}
+	return v
+}
+
+// setDeepObjectParam reads a `deepObject` style query param from the request,
+// validates it against the param's schema, and sets it on the field `f`.
+func setDeepObjectParam(r Registry, ctx Context, op *Operation, f reflect.Value, p *paramFieldInfo, pb *PathBuffer, res *ValidateResult) {
+	pb.Reset()
+	pb.Push(p.Loc)
+	pb.Push(p.Name)
+
+	u := ctx.URL()
+	parsed, err := parseDeepObjectQuery(u.Query(), p.Name)
+	if err != nil {
+		res.Add(pb, u.RawQuery, err.Error())
+		return
+	}
+
+	if parsed == nil {
+		if !op.SkipValidateParams && p.Required {
+			res.Add(pb, "", "required "+p.Loc+" parameter is missing")
+		}
+		return
+	}
+
+	pv := coerceDeepObject(r, p.Schema, parsed)
+	if !op.SkipValidateParams {
+		count := len(res.Errors)
+		Validate(r, p.Schema, pb, ModeWriteToServer, pv, res)
+		if len(res.Errors) > count {
+			return
+		}
+	}
+
+	if p.Pointer {
+		ptr := reflect.New(p.Type)
+		f.Set(ptr)
+		f = ptr.Elem()
+	}
+
+	// The values have been coerced to the right types, so a round-trip through
+	// JSON gets them into the field, including nested structs and maps.
+	b, err := json.Marshal(pv)
+	if err == nil {
+		err = json.Unmarshal(b, f.Addr().Interface())
+	}
+	if err != nil {
+		res.Add(pb, parsed, "invalid value: "+err.Error())
+	}
+}
@@ -1,6 +1,8 @@
 package huma
 
 import (
+	"compress/gzip"
+	"compress/zlib"
 	"encoding/json"
 	"io"
 )
@@ -32,10 +34,33 @@ var DefaultFormats = map[string]Format{
 	"json":             DefaultJSONFormat,
 }
 
+// DefaultContentDecoders is a map of default request body decoders that can
+// be set in the API's `Config.ContentDecoders` map, used to transparently
+// decompress request bodies based on the `Content-Encoding` header. This is
+// copied by the `DefaultConfig` function and can be modified before creating
+// any APIs to add or remove encodings. For example, to add support for Brotli:
+//
+//	huma.DefaultContentDecoders["br"] = func(r io.Reader) (io.ReadCloser, error) {
+//		return io.NopCloser(brotli.NewReader(r)), nil
+//	}
+//
+// The operation's `MaxBodyBytes` limit applies to the decoded body, so highly
+// compressed payloads cannot be used to exhaust server memory.
+var DefaultContentDecoders = map[string]ContentDecoder{
+	"gzip": func(r io.Reader) (io.ReadCloser, error) {
+		return gzip.NewReader(r)
+	},
+	"deflate": func(r io.Reader) (io.ReadCloser, error) {
+		// HTTP `deflate` is actually the zlib format, see RFC 9110 section 8.4.1.2.
+		return zlib.NewReader(r)
+	},
+}
+
 // DefaultConfig returns a default configuration for a new API. It is a good
 // starting point for creating your own configuration. It supports the JSON
-// format out of the box. The registry uses references for structs and a link
-// transformer is included to add `$schema` fields and links into responses. The
+// format and gzip/deflate encoded request bodies out of the box. The registry
+// uses references for structs and a link transformer is included to add
+// `$schema` fields and links into responses. The
 // `/openapi.[json|yaml]`, `/docs`, and `/schemas` paths are set up to serve the
 // OpenAPI spec, docs UI, and schemas respectively.
 //
@@ -56,6 +81,12 @@ func DefaultConfig(title, version string) Config {
 
 	registry := NewMapRegistry(schemaPrefix, DefaultSchemaNamer)
 
+	// Copy the decoders so changing them for one API doesn't affect others.
+	decoders := make(map[string]ContentDecoder, len(DefaultContentDecoders))
+	for k, v := range DefaultContentDecoders {
+		decoders[k] = v
+	}
+
 	return Config{
 		OpenAPI: &OpenAPI{
 			OpenAPI: "3.1.0",
@@ -67,11 +98,12 @@ func DefaultConfig(title, version string) Config {
 				Schemas: registry,
 			},
 		},
-		OpenAPIPath:   "/openapi",
-		DocsPath:      "/docs",
-		SchemasPath:   schemasPath,
-		Formats:       DefaultFormats,
-		DefaultFormat: "application/json",
+		OpenAPIPath:     "/openapi",
+		DocsPath:        "/docs",
+		SchemasPath:     schemasPath,
+		Formats:         DefaultFormats,
+		DefaultFormat:   "application/json",
+		ContentDecoders: decoders,
 		CreateHooks: []func(Config) Config{
 			func(c Config) Config {
 				// Add a link transformer to the API. This adds `Link` headers and
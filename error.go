@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
 	"strconv"
 )
 
@@ -150,6 +151,12 @@ type StatusError interface {
 	Error() string
 }
 
+// ErrorFactory creates an error model for the given status code, message, and
+// optional error details. It has the same signature as `NewError` and is used
+// to register a custom error type for specific status codes via
+// `Operation.ErrorModels`.
+type ErrorFactory func(status int, msg string, errs ...error) StatusError
+
 // HeadersError is an error that has HTTP headers. When returned from an
 // operation handler, these headers are set on the response before sending it
 // to the client. Use `ErrorWithHeaders` to wrap an error like
@@ -249,10 +256,12 @@ var NewError = func(status int, msg string, errs ...error) StatusError {
 }
 
 // WriteErr writes an error response with the given context, using the
-// configured error type and with the given status code and message. It is
-// marshaled using the API's content negotiation methods.
+// configured error type and with the given status code and message. If the
+// operation registered a custom error model for the status code via
+// `Operation.ErrorModels`, then that is used instead. It is marshaled using
+// the API's content negotiation methods.
 func WriteErr(api API, ctx Context, status int, msg string, errs ...error) error {
-	var err any = NewError(status, msg, errs...)
+	var err any = newOperationError(ctx.Operation(), status, msg, errs...)
 
 	ct, negotiateErr := api.Negotiate(ctx.Header("Accept"))
 	if negotiateErr != nil {
@@ -272,6 +281,76 @@ func WriteErr(api API, ctx Context, status int, msg string, errs ...error) error
 	return api.Marshal(ctx.BodyWriter(), ct, tval)
 }
 
+// newOperationError creates a new error using the operation's registered
+// error model for the status code, falling back to `NewError`.
+func newOperationError(op *Operation, status int, msg string, errs ...error) StatusError {
+	if f := op.errorModel(status); f != nil {
+		return f(status, msg, errs...)
+	}
+	return NewError(status, msg, errs...)
+}
+
+// errorModel returns the operation's registered error model for the status
+// code, if any. The operation may be nil.
+func (o *Operation) errorModel(status int) ErrorFactory {
+	if o == nil {
+		return nil
+	}
+	return o.ErrorModels[status]
+}
+
+// asOperationError converts an error returned by a handler, e.g. from
+// `huma.Error409Conflict`, into the operation's registered error model for
+// its status code, so the response matches the documented schema. Errors
+// which already use the registered model are returned as-is.
+func asOperationError(op *Operation, se StatusError) StatusError {
+	status := se.GetStatus()
+	f := op.errorModel(status)
+	if f == nil || reflect.TypeOf(f(status, "")) == reflect.TypeOf(se) {
+		return se
+	}
+	if m, ok := se.(*ErrorModel); ok {
+		errs := make([]error, len(m.Errors))
+		for i, e := range m.Errors {
+			errs[i] = e
+		}
+		return f(status, m.Detail, errs...)
+	}
+	return f(status, se.Error())
+}
+
+// ErrorResponse returns the documentation for an error response with the
+// given status code. The operation's error model for the status code from
+// `Operation.ErrorModels` is used if registered, otherwise the type returned
+// by `NewError`. The operation may be nil. This is useful for middleware
+// which writes its own errors, so they are documented like the errors of
+// the operation itself.
+//
+//	op.Responses["429"] = huma.ErrorResponse(oapi.Components.Schemas, op, http.StatusTooManyRequests)
+func ErrorResponse(registry Registry, op *Operation, status int) *Response {
+	var errModel StatusError
+	hint := "Error"
+	if f := op.errorModel(status); f != nil {
+		errModel = f(status, "")
+		hint = op.OperationID + strconv.Itoa(status) + "Error"
+	} else {
+		errModel = NewError(status, "")
+	}
+	contentType := "application/json"
+	if ctf, ok := errModel.(ContentTypeFilter); ok {
+		contentType = ctf.ContentType(contentType)
+	}
+	t := deref(reflect.TypeOf(errModel))
+	return &Response{
+		Description: http.StatusText(status),
+		Content: map[string]*MediaType{
+			contentType: {
+				Schema: registry.Schema(t, true, getHint(t, "", hint)),
+			},
+		},
+	}
+}
+
 // Status304NotModified returns a 304. This is not really an error, but
 // provides a way to send non-default responses.
 func Status304NotModified() StatusError {
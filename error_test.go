@@ -12,6 +12,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humaflow"
+	"github.com/danielgtaylor/huma/v2/adapters/humaflow/flow"
 	"github.com/danielgtaylor/huma/v2/humatest"
 )
 
@@ -137,3 +139,40 @@ func TestErrorWithHeaders(t *testing.T) {
 	assert.Equal(t, "bar", resp.Header().Get("Another"))
 	assert.Contains(t, resp.Body.String(), "test")
 }
+
+func TestHandleUnmatched(t *testing.T) {
+	r, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+	humaflow.HandleUnmatched(r.(*flow.Mux), api)
+
+	for _, op := range []huma.Operation{
+		{OperationID: "get-thing", Method: http.MethodGet, Path: "/things/{id}"},
+		{OperationID: "put-thing", Method: http.MethodPut, Path: "/things/{id}"},
+		{OperationID: "get-special", Method: http.MethodGet, Path: "/things/special"},
+		{OperationID: "get-file", Method: http.MethodGet, Path: "/files/{path...}"},
+	} {
+		huma.Register(api, op, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+			return nil, nil
+		})
+	}
+
+	resp := api.Delete("/things/123")
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+	assert.Equal(t, "GET, PUT", resp.Header().Get("Allow"))
+	assert.Equal(t, "application/problem+json", resp.Header().Get("Content-Type"))
+	assert.Contains(t, resp.Body.String(), "method DELETE not allowed")
+
+	// Static paths are preferred over path params.
+	resp = api.Delete("/things/special")
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+	assert.Equal(t, "GET", resp.Header().Get("Allow"))
+
+	resp = api.Post("/files/a/b/c")
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+	assert.Equal(t, "GET", resp.Header().Get("Allow"))
+
+	resp = api.Get("/missing")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	assert.Empty(t, resp.Header().Get("Allow"))
+	assert.Equal(t, "application/problem+json", resp.Header().Get("Content-Type"))
+	assert.Contains(t, resp.Body.String(), "no operation found")
+}
@@ -1,6 +1,7 @@
 package huma
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"mime/multipart"
@@ -54,6 +55,12 @@ func (v MimeTypeValidator) Validate(fh *multipart.FileHeader, location string) (
 		file.Seek(int64(0), io.SeekStart)
 		mimeType = http.DetectContentType(buffer)
 	}
+	return mimeType, v.ValidateType(mimeType, location)
+}
+
+// ValidateType checks a mime type, e.g. from a streamed multipart part,
+// against the expected content type.
+func (v MimeTypeValidator) ValidateType(mimeType, location string) *ErrorDetail {
 	accept := false
 	for _, m := range v.accept {
 		if m == "text/plain" || m == "application/octet-stream" {
@@ -72,9 +79,9 @@ func (v MimeTypeValidator) Validate(fh *multipart.FileHeader, location string) (
 	}
 
 	if accept {
-		return mimeType, nil
+		return nil
 	} else {
-		return mimeType, &ErrorDetail{
+		return &ErrorDetail{
 			Message: fmt.Sprintf(
 				"Invalid mime type: got %v, expected %v",
 				mimeType, strings.Join(v.accept, ","),
@@ -85,6 +92,19 @@ func (v MimeTypeValidator) Validate(fh *multipart.FileHeader, location string) (
 	}
 }
 
+// checkFileSize returns an error if the file is larger than the schema's
+// `maxLength`, which is the maximum file size in bytes.
+func checkFileSize(fh *multipart.FileHeader, location string, s *Schema) *ErrorDetail {
+	if s != nil && s.MaxLength != nil && fh.Size > int64(*s.MaxLength) {
+		return &ErrorDetail{
+			Message:  fmt.Sprintf("File too large, expected size <= %d bytes", *s.MaxLength),
+			Location: location,
+			Value:    fh.Size,
+		}
+	}
+	return nil
+}
+
 func (m *MultipartFormFiles[T]) readFile(
 	fh *multipart.FileHeader,
 	location string,
@@ -108,20 +128,24 @@ func (m *MultipartFormFiles[T]) readFile(
 }
 
 func (m *MultipartFormFiles[T]) readSingleFile(key string, opMediaType *MediaType) (FormFile, *ErrorDetail) {
+	location := "body." + key
 	fileHeaders := m.Form.File[key]
 	if len(fileHeaders) == 0 {
 		if opMediaType.Schema.requiredMap[key] {
-			return FormFile{}, &ErrorDetail{Message: "File required", Location: key}
+			return FormFile{}, &ErrorDetail{Message: "File required", Location: location}
 		} else {
 			return FormFile{}, nil
 		}
 	} else if len(fileHeaders) == 1 {
+		if err := checkFileSize(fileHeaders[0], location, opMediaType.Schema.Properties[key]); err != nil {
+			return FormFile{}, err
+		}
 		validator := NewMimeTypeValidator(opMediaType.Encoding[key])
-		return m.readFile(fileHeaders[0], key, validator)
+		return m.readFile(fileHeaders[0], location, validator)
 	}
 	return FormFile{}, &ErrorDetail{
 		Message:  "Multiple files received but only one was expected",
-		Location: key,
+		Location: location,
 	}
 }
 
@@ -132,13 +156,28 @@ func (m *MultipartFormFiles[T]) readMultipleFiles(key string, opMediaType *Media
 		errors []error
 	)
 	if opMediaType.Schema.requiredMap[key] && len(fileHeaders) == 0 {
-		return nil, []error{&ErrorDetail{Message: "At least one file is required", Location: key}}
+		return nil, []error{&ErrorDetail{Message: "At least one file is required", Location: "body." + key}}
+	}
+	schema := opMediaType.Schema.Properties[key]
+	if schema != nil && schema.MaxItems != nil && len(fileHeaders) > *schema.MaxItems {
+		return nil, []error{&ErrorDetail{
+			Message:  fmt.Sprintf("Too many files, expected at most %d", *schema.MaxItems),
+			Location: "body." + key,
+			Value:    len(fileHeaders),
+		}}
 	}
 	validator := NewMimeTypeValidator(opMediaType.Encoding[key])
 	for i, fh := range fileHeaders {
+		location := fmt.Sprintf("body.%s[%d]", key, i)
+		if schema != nil {
+			if err := checkFileSize(fh, location, schema.Items); err != nil {
+				errors = append(errors, err)
+				continue
+			}
+		}
 		file, err := m.readFile(
 			fh,
-			fmt.Sprintf("%s[%d]", key, i),
+			location,
 			validator,
 		)
 		if err != nil {
@@ -154,9 +193,33 @@ func (m *MultipartFormFiles[T]) Data() *T {
 	return m.data
 }
 
+// multipartFormDecoder is used to decode a `MultipartFormFiles` of any type.
+type multipartFormDecoder interface {
+	decode(r Registry, opMediaType *MediaType) []error
+}
+
 // Decodes multipart.Form data into *T, returning []*ErrorDetail if any
-// Schema is used to check for validation constraints
+// Schema is used to check for validation constraints. Non-file fields are
+// coerced into their types and validated against the schemas of `T`.
 func (m *MultipartFormFiles[T]) Decode(opMediaType *MediaType) []error {
+	// The field schemas may refer to the API's registry, which is not
+	// available here, so generate them again from `T` for validation.
+	registry := NewMapRegistry("#/components/schemas/", DefaultSchemaNamer)
+	fields := multiPartFormFileSchema(registry, reflect.TypeOf(m.data).Elem(), "")
+	schema := *opMediaType.Schema
+	schema.Properties = make(map[string]*Schema, len(opMediaType.Schema.Properties))
+	for k, v := range opMediaType.Schema.Properties {
+		if fs := fields.Properties[k]; fs != nil && !isFileSchema(fs) && !(fs.Type == TypeArray && isFileSchema(fs.Items)) {
+			v = fs
+		}
+		schema.Properties[k] = v
+	}
+	mediaType := *opMediaType
+	mediaType.Schema = &schema
+	return m.decode(registry, &mediaType)
+}
+
+func (m *MultipartFormFiles[T]) decode(r Registry, opMediaType *MediaType) []error {
 	var (
 		dataType = reflect.TypeOf(m.data).Elem()
 		value    = reflect.New(dataType)
@@ -165,6 +228,9 @@ func (m *MultipartFormFiles[T]) Decode(opMediaType *MediaType) []error {
 	for i := 0; i < dataType.NumField(); i++ {
 		field := value.Elem().Field(i)
 		structField := dataType.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
 		key := structField.Tag.Get("form")
 		if key == "" {
 			key = structField.Name
@@ -186,13 +252,80 @@ func (m *MultipartFormFiles[T]) Decode(opMediaType *MediaType) []error {
 			field.Set(reflect.ValueOf(files))
 
 		default:
-			continue
+			errors = append(errors, m.readValue(r, key, opMediaType, field)...)
 		}
 	}
 	m.data = value.Interface().(*T)
 	return errors
 }
 
+// readValue reads a non-file form field, coercing the text parts into the
+// type described by its schema, e.g. an integer or a JSON encoded object.
+func (m *MultipartFormFiles[T]) readValue(r Registry, key string, opMediaType *MediaType, field reflect.Value) []error {
+	location := "body." + key
+	schema := opMediaType.Schema.Properties[key]
+	values := m.Form.Value[key]
+	if len(values) == 0 || schema == nil {
+		if opMediaType.Schema.requiredMap[key] {
+			return []error{&ErrorDetail{Message: "Field required", Location: location}}
+		}
+		return nil
+	}
+
+	var value any
+	if isJSONFormField(field.Type()) {
+		if err := json.Unmarshal([]byte(values[0]), &value); err != nil {
+			return []error{&ErrorDetail{
+				Message:  "invalid JSON: " + err.Error(),
+				Location: location,
+				Value:    values[0],
+			}}
+		}
+	} else {
+		value = coerceDeepObject(r, schema, values)
+	}
+
+	if r != nil {
+		pb := NewPathBuffer([]byte{}, 0)
+		pb.Push("body")
+		pb.Push(key)
+		res := &ValidateResult{}
+		Validate(r, schema, pb, ModeWriteToServer, value, res)
+		if len(res.Errors) > 0 {
+			return res.Errors
+		}
+	}
+
+	b, err := json.Marshal(value)
+	if err == nil {
+		err = json.Unmarshal(b, field.Addr().Interface())
+	}
+	if err != nil {
+		return []error{&ErrorDetail{
+			Message:  err.Error(),
+			Location: location,
+			Value:    values[0],
+		}}
+	}
+	return nil
+}
+
+// isJSONFormField returns whether a non-file form field is sent as a JSON
+// encoded part, e.g. for structs, rather than as plain text.
+func isJSONFormField(t reflect.Type) bool {
+	t = deref(t)
+	if t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8 {
+		t = deref(t.Elem())
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		return t != timeType
+	case reflect.Map, reflect.Interface:
+		return true
+	}
+	return false
+}
+
 func formDataFieldName(f reflect.StructField) string {
 	name := f.Name
 	if formDataKey := f.Tag.Get("form"); formDataKey != "" {
@@ -201,16 +334,22 @@ func formDataFieldName(f reflect.StructField) string {
 	return name
 }
 
-func multiPartFormFileSchema(t reflect.Type) *Schema {
+// multiPartFormFileSchema generates the schema of a multipart form. Files
+// are binary strings, while any other fields are documented using their
+// type's schema, with `hint` used to name any new schemas.
+func multiPartFormFileSchema(registry Registry, t reflect.Type, hint string) *Schema {
 	nFields := t.NumField()
 	schema := &Schema{
 		Type:        "object",
 		Properties:  make(map[string]*Schema, nFields),
 		requiredMap: make(map[string]bool, nFields),
 	}
-	requiredFields := make([]string, nFields)
+	requiredFields := make([]string, 0, nFields)
 	for i := 0; i < nFields; i++ {
 		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
 		name := formDataFieldName(f)
 
 		switch {
@@ -218,16 +357,20 @@ func multiPartFormFileSchema(t reflect.Type) *Schema {
 			schema.Properties[name] = multiPartFileSchema(f)
 		case f.Type == reflect.TypeOf([]FormFile{}):
 			schema.Properties[name] = &Schema{
-				Type:  "array",
-				Items: multiPartFileSchema(f),
+				Type:     "array",
+				Items:    multiPartFileSchema(f),
+				MaxItems: intTag(f, "maxItems"),
 			}
 		default:
-			// Should we panic if [T] struct defines fields with unsupported types ?
-			continue
+			fs := SchemaFromField(registry, f, getHint(t, f.Name, hint+f.Name))
+			if fs == nil {
+				continue
+			}
+			schema.Properties[name] = fs
 		}
 
 		if _, ok := f.Tag.Lookup("required"); ok && boolTag(f, "required") {
-			requiredFields[i] = name
+			requiredFields = append(requiredFields, name)
 			schema.requiredMap[name] = true
 		}
 	}
@@ -241,6 +384,8 @@ func multiPartFileSchema(f reflect.StructField) *Schema {
 		Format:          "binary",
 		Description:     f.Tag.Get("doc"),
 		ContentEncoding: "binary",
+		// The maximum file size in bytes.
+		MaxLength: intTag(f, "maxLength"),
 	}
 }
 
@@ -249,10 +394,20 @@ func multiPartContentEncoding(t reflect.Type) map[string]*Encoding {
 	encoding := make(map[string]*Encoding, nFields)
 	for i := 0; i < nFields; i++ {
 		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
 		name := formDataFieldName(f)
 		contentType := f.Tag.Get("contentType")
 		if contentType == "" {
-			contentType = "application/octet-stream"
+			switch {
+			case f.Type == reflect.TypeOf(FormFile{}) || f.Type == reflect.TypeOf([]FormFile{}):
+				contentType = "application/octet-stream"
+			case isJSONFormField(f.Type):
+				contentType = "application/json"
+			default:
+				contentType = "text/plain"
+			}
 		}
 		encoding[name] = &Encoding{
 			ContentType: contentType,
@@ -0,0 +1,86 @@
+package huma
+
+import "strings"
+
+// OperationModifier is implemented by APIs which modify operations before
+// they are registered, like `huma.Group`. `huma.Register` calls
+// `ModifyOperation` before doing anything else with the operation.
+type OperationModifier interface {
+	ModifyOperation(op *Operation)
+}
+
+// Group is a collection of operations which share a path prefix, operation
+// modifiers and middleware. It wraps an API and can be used anywhere an API
+// is expected, e.g. with `huma.Register` or `huma.Get`. Operations are added
+// to the same OpenAPI document as the wrapped API.
+//
+//	grp := huma.NewGroup(api, "/v1/orgs/{org-id}")
+//	grp.UseModifier(func(op *huma.Operation) {
+//		op.Tags = append(op.Tags, "Orgs")
+//	})
+//	grp.UseMiddleware(authMiddleware)
+//
+//	// Registers `GET /v1/orgs/{org-id}/users`.
+//	huma.Get(grp, "/users", listUsers)
+//
+// Groups may be nested, in which case the prefixes, modifiers and middleware
+// of the inner group are applied after those of the outer group.
+type Group struct {
+	API
+	prefix      string
+	modifiers   []func(op *Operation)
+	middlewares Middlewares
+}
+
+// NewGroup creates a new group of operations on the API, using the given
+// path prefix which may contain path params. Path params from the prefix
+// still need to be declared on each operation's input struct, or documented
+// by a group modifier.
+func NewGroup(api API, prefix string) *Group {
+	return &Group{API: api, prefix: strings.TrimSuffix(prefix, "/")}
+}
+
+// Prefix returns the full path prefix of the group, including the prefixes
+// of any parent groups.
+func (g *Group) Prefix() string {
+	if parent, ok := g.API.(*Group); ok {
+		return parent.Prefix() + g.prefix
+	}
+	return g.prefix
+}
+
+// UseModifier adds an operation modifier to the group. Modifiers are called
+// in the order they are added, after the group prefix has been applied, and
+// may e.g. set tags, security requirements, errors or defaults for all of the
+// operations in the group.
+func (g *Group) UseModifier(modifier func(op *Operation)) {
+	g.modifiers = append(g.modifiers, modifier)
+}
+
+// ModifyOperation applies the group's prefix and modifiers to the operation,
+// including those of any parent groups.
+func (g *Group) ModifyOperation(op *Operation) {
+	op.Path = g.prefix + op.Path
+	if parent, ok := g.API.(OperationModifier); ok {
+		parent.ModifyOperation(op)
+	}
+	for _, modifier := range g.modifiers {
+		modifier(op)
+	}
+}
+
+// UseMiddleware appends a middleware handler to the group's middleware stack.
+// Group middleware runs after the middleware of the wrapped API and only for
+// operations registered on the group.
+func (g *Group) UseMiddleware(middlewares ...func(ctx Context, next func(Context))) {
+	g.middlewares = append(g.middlewares, middlewares...)
+}
+
+// Middlewares returns the middleware of the wrapped API followed by the
+// group's own middleware.
+func (g *Group) Middlewares() Middlewares {
+	parent := g.API.Middlewares()
+	m := make(Middlewares, 0, len(parent)+len(g.middlewares))
+	m = append(m, parent...)
+	return append(m, g.middlewares...)
+}
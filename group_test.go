@@ -0,0 +1,103 @@
+package huma_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+)
+
+func TestGroup(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	calls := []string{}
+	api.UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+		calls = append(calls, "api")
+		next(ctx)
+	})
+
+	orgs := huma.NewGroup(api, "/v1/orgs/{org-id}")
+	orgs.UseModifier(func(op *huma.Operation) {
+		op.Tags = append(op.Tags, "Orgs")
+		op.Security = []map[string][]string{{"bearer": {}}}
+		op.Errors = append(op.Errors, http.StatusForbidden)
+		op.Parameters = append(op.Parameters, &huma.Param{
+			Name:        "org-id",
+			In:          "path",
+			Description: "The organization ID",
+			Required:    true,
+			Schema:      &huma.Schema{Type: "string"},
+		})
+	})
+	orgs.UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+		calls = append(calls, "orgs")
+		next(ctx)
+	})
+
+	projects := huma.NewGroup(orgs, "/projects/{project-id}")
+	projects.UseModifier(func(op *huma.Operation) {
+		op.Tags = append(op.Tags, "Projects")
+	})
+	projects.UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+		calls = append(calls, "projects")
+		next(ctx)
+	})
+
+	assert.Equal(t, "/v1/orgs/{org-id}/projects/{project-id}", projects.Prefix())
+
+	huma.Get(orgs, "/users", func(ctx context.Context, input *struct {
+		OrgID string `path:"org-id"`
+	}) (*struct{ Body []string }, error) {
+		return &struct{ Body []string }{Body: []string{input.OrgID}}, nil
+	})
+
+	huma.Register(projects, huma.Operation{
+		OperationID: "get-project",
+		Method:      http.MethodGet,
+		Path:        "",
+		Tags:        []string{"Things"},
+	}, func(ctx context.Context, input *struct {
+		OrgID     string `path:"org-id"`
+		ProjectID string `path:"project-id"`
+	}) (*struct{ Body string }, error) {
+		return &struct{ Body string }{Body: input.OrgID + "/" + input.ProjectID}, nil
+	})
+
+	// Everything ends up in the same OpenAPI document.
+	oapi := api.OpenAPI()
+	users := oapi.Paths["/v1/orgs/{org-id}/users"]
+	require.NotNil(t, users)
+	assert.Equal(t, "list-v1-orgs-by-org-id-users", users.Get.OperationID)
+	assert.Equal(t, []string{"Orgs"}, users.Get.Tags)
+	assert.NotNil(t, users.Get.Responses["403"])
+
+	// The shared path param is documented once, by the group.
+	require.Len(t, users.Get.Parameters, 1)
+	assert.Equal(t, "The organization ID", users.Get.Parameters[0].Description)
+
+	project := oapi.Paths["/v1/orgs/{org-id}/projects/{project-id}"]
+	require.NotNil(t, project)
+	assert.Equal(t, []string{"Things", "Orgs", "Projects"}, project.Get.Tags)
+	assert.Equal(t, []map[string][]string{{"bearer": {}}}, project.Get.Security)
+	assert.Len(t, project.Get.Parameters, 2)
+
+	resp := api.Get("/v1/orgs/o1/users")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `["o1"]`, resp.Body.String())
+	assert.Equal(t, []string{"api", "orgs"}, calls)
+
+	calls = nil
+	resp = api.Get("/v1/orgs/o1/projects/p1")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `"o1/p1"`, resp.Body.String())
+	assert.Equal(t, []string{"api", "orgs", "projects"}, calls)
+
+	// Group middleware does not leak into the parent API.
+	assert.Len(t, api.Middlewares(), 1)
+	assert.Len(t, orgs.Middlewares(), 2)
+}
@@ -18,17 +18,20 @@ import (
 	"net/http"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/danielgtaylor/huma/v2/casing"
+	"github.com/danielgtaylor/huma/v2/queryparam"
 )
 
 var errDeadlineUnsupported = fmt.Errorf("%w", http.ErrNotSupported)
 
 var bodyCallbackType = reflect.TypeOf(func(Context) {})
+var readerType = reflect.TypeOf((*io.Reader)(nil)).Elem()
 var cookieType = reflect.TypeOf((*http.Cookie)(nil)).Elem()
 var fmtStringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
 var stringType = reflect.TypeOf("")
@@ -95,14 +98,102 @@ type StreamResponse struct {
 	Body func(ctx Context)
 }
 
+// ParamUnmarshaler can be implemented by custom types used as path, query,
+// header, or cookie params in order to parse themselves from the raw string
+// value sent by the client. It takes precedence over the standard library's
+// `encoding.TextUnmarshaler`, which is also supported. Any returned error is
+// sent to the client as a validation error for the param.
+//
+//	type OrgID struct {
+//		Prefix string
+//		ID     int
+//	}
+//
+//	func (o *OrgID) UnmarshalParam(value string) error {
+//		prefix, id, _ := strings.Cut(value, "-")
+//		n, err := strconv.Atoi(id)
+//		o.Prefix, o.ID = prefix, n
+//		return err
+//	}
+//
+// Combine this with `huma.SchemaProvider` to document the param's format,
+// otherwise it is documented as a plain string.
+type ParamUnmarshaler interface {
+	UnmarshalParam(value string) error
+}
+
+var paramUnmarshalerType = reflect.TypeOf((*ParamUnmarshaler)(nil)).Elem()
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+var schemaProviderType = reflect.TypeOf((*SchemaProvider)(nil)).Elem()
+
+// isParamUnmarshaler returns whether the given param type parses itself. The
+// `time.Time` type is handled separately to support the `timeFormat` tag.
+func isParamUnmarshaler(t reflect.Type) bool {
+	if t == timeType {
+		return false
+	}
+	pt := reflect.PointerTo(t)
+	return pt.Implements(paramUnmarshalerType) || pt.Implements(textUnmarshalerType)
+}
+
+// hasStringSchema returns whether a custom param type is documented with a
+// string schema, either its own or a built-in one like for `netip.Addr`.
+// Other types would be documented as their Go representation, e.g. an object
+// for a struct, rather than the string clients need to send.
+func hasStringSchema(t reflect.Type) bool {
+	switch t {
+	case urlType, ipType, ipAddrType:
+		return true
+	}
+	return reflect.PointerTo(t).Implements(schemaProviderType)
+}
+
+// unmarshalParam parses a custom param type, or a slice of them, from the
+// request value. It returns the value to use for validation.
+func unmarshalParam(f reflect.Value, p *paramFieldInfo, value string, values []string) (any, error) {
+	if p.UnmarshalItems {
+		if values == nil {
+			values = strings.Split(value, ",")
+		}
+		s := reflect.MakeSlice(f.Type(), len(values), len(values))
+		for i, item := range values {
+			if err := unmarshalParamItem(s.Index(i), item); err != nil {
+				return nil, err
+			}
+		}
+		f.Set(s)
+		return values, nil
+	}
+	return value, unmarshalParamItem(f, value)
+}
+
+func unmarshalParamItem(f reflect.Value, value string) error {
+	switch u := f.Addr().Interface().(type) {
+	case ParamUnmarshaler:
+		return u.UnmarshalParam(value)
+	case encoding.TextUnmarshaler:
+		return u.UnmarshalText([]byte(value))
+	}
+	return nil
+}
+
 type paramFieldInfo struct {
-	Type       reflect.Type
-	Name       string
-	Loc        string
-	Required   bool
-	Default    string
-	TimeFormat string
-	Schema     *Schema
+	Type     reflect.Type
+	Name     string
+	Loc      string
+	Required bool
+	Pointer  bool
+	Explode  bool
+	Style    string
+
+	// Unmarshaler is set when the param type (or each slice item type if
+	// UnmarshalItems is set) implements `ParamUnmarshaler` or
+	// `encoding.TextUnmarshaler`.
+	Unmarshaler    bool
+	UnmarshalItems bool
+	Default        string
+	TimeFormat     string
+	Schema         *Schema
 }
 
 func findParams(registry Registry, op *Operation, t reflect.Type) *findResult[*paramFieldInfo] {
@@ -111,18 +202,16 @@ func findParams(registry Registry, op *Operation, t reflect.Type) *findResult[*p
 			return nil
 		}
 
-		if f.Type.Kind() == reflect.Pointer {
-			// TODO: support pointers? The problem is that when we dynamically
-			// create an instance of the input struct the `params.Every(...)`
-			// call cannot set them as the value is `reflect.Invalid` unless
-			// dynamically allocated, but we don't know when to allocate until
-			// after the `Every` callback has run. Doable, but a bigger change.
-			panic("pointers are not supported for path/query/header parameters")
-		}
+		pfi := &paramFieldInfo{}
 
-		pfi := &paramFieldInfo{
-			Type: f.Type,
+		if f.Type.Kind() == reflect.Pointer {
+			// Pointers let the handler tell the difference between a param which
+			// was not sent and one sent with the zero value. They are only
+			// allocated when the param is present in the request.
+			pfi.Pointer = true
+			f.Type = f.Type.Elem()
 		}
+		pfi.Type = f.Type
 
 		if def := f.Tag.Get("default"); def != "" {
 			pfi.Default = def
@@ -138,9 +227,13 @@ func findParams(registry Registry, op *Operation, t reflect.Type) *findResult[*p
 			pfi.Loc = "query"
 			name = q
 			// If `in` is `query` then `explode` defaults to true. Parsing is *much*
-			// easier if we use comma-separated values, so we disable explode.
-			nope := false
-			explode = &nope
+			// easier if we use comma-separated values, so we disable explode unless
+			// the field opts in to repeated keys like `?tag=a&tag=b`.
+			pfi.Explode = boolTag(f, "explode")
+			if pfi.Explode && f.Type.Kind() != reflect.Slice {
+				panic("explode is only supported for slice query parameters")
+			}
+			explode = &pfi.Explode
 		} else if h := f.Tag.Get("header"); h != "" {
 			pfi.Loc = "header"
 			name = h
@@ -157,8 +250,45 @@ func findParams(registry Registry, op *Operation, t reflect.Type) *findResult[*p
 			return nil
 		}
 
+		if isParamUnmarshaler(f.Type) {
+			pfi.Unmarshaler = true
+			if !hasStringSchema(f.Type) {
+				f.Type = stringType
+			}
+		} else if f.Type.Kind() == reflect.Slice && isParamUnmarshaler(f.Type.Elem()) {
+			pfi.Unmarshaler = true
+			pfi.UnmarshalItems = true
+			if !hasStringSchema(f.Type.Elem()) {
+				f.Type = reflect.SliceOf(stringType)
+			}
+		}
+
+		if pfi.Loc == "query" && pfi.Default != "" && !pfi.Unmarshaler && !hasStringSchema(f.Type) {
+			t := deref(f.Type)
+			if k := t.Kind(); k == reflect.Map || (k == reflect.Struct && t != timeType) {
+				// Objects are sent as `deepObject` params, which have no way to
+				// express a default value.
+				panic(fmt.Errorf("default is not supported for deepObject query param %s", name))
+			}
+		}
+
 		pfi.Schema = SchemaFromField(registry, f, "")
 
+		var style string
+		if pfi.Loc == "query" && pfi.Schema != nil && !pfi.Unmarshaler {
+			s := pfi.Schema
+			for s.Ref != "" {
+				s = registry.SchemaFromRef(s.Ref)
+			}
+			if s.Type == TypeObject {
+				// Maps and structs are sent as e.g. `filter[status]=open`.
+				style = styleDeepObject
+				pfi.Style = style
+				pfi.Explode = true
+				explode = &pfi.Explode
+			}
+		}
+
 		var example any
 		if e := f.Tag.Get("example"); e != "" {
 			example = jsonTagValue(registry, f.Type.Name(), pfi.Schema, f.Tag.Get("example"))
@@ -182,7 +312,7 @@ func findParams(registry Registry, op *Operation, t reflect.Type) *findResult[*p
 			pfi.TimeFormat = timeFormat
 		}
 
-		if !boolTag(f, "hidden") {
+		if !boolTag(f, "hidden") && !hasParam(op, name, pfi.Loc) {
 			desc := ""
 			if pfi.Schema != nil {
 				// If the schema has a description, use it. Some tools will not show
@@ -195,6 +325,7 @@ func findParams(registry Registry, op *Operation, t reflect.Type) *findResult[*p
 				Name:        name,
 				Description: desc,
 				In:          pfi.Loc,
+				Style:       style,
 				Explode:     explode,
 				Required:    pfi.Required,
 				Schema:      pfi.Schema,
@@ -206,6 +337,17 @@ func findParams(registry Registry, op *Operation, t reflect.Type) *findResult[*p
 	}, false, "Body")
 }
 
+// hasParam returns whether the operation already documents a parameter,
+// e.g. a shared path param documented by a group modifier.
+func hasParam(op *Operation, name, in string) bool {
+	for _, p := range op.Parameters {
+		if p.Name == name && p.In == in {
+			return true
+		}
+	}
+	return false
+}
+
 func findResolvers(resolverType, t reflect.Type) *findResult[bool] {
 	return findInType(t, func(t reflect.Type, path []int) bool {
 		tp := reflect.PointerTo(t)
@@ -218,6 +360,13 @@ func findResolvers(resolverType, t reflect.Type) *findResult[bool] {
 
 func findDefaults(registry Registry, t reflect.Type) *findResult[any] {
 	return findInType(t, nil, func(sf reflect.StructField, i []int) any {
+		for _, loc := range []string{"path", "query", "header", "cookie"} {
+			if sf.Tag.Get(loc) != "" {
+				// Params apply their own defaults when parsing the request, and
+				// pointer params are allocated for them.
+				return nil
+			}
+		}
 		if d := sf.Tag.Get("default"); d != "" {
 			if sf.Type.Kind() == reflect.Pointer {
 				panic("pointers cannot have default values")
@@ -275,7 +424,14 @@ func (r *findResult[T]) every(current reflect.Value, path []int, v T, f func(ref
 
 	switch current.Kind() {
 	case reflect.Struct:
-		r.every(reflect.Indirect(current.Field(path[0])), path[1:], v, f)
+		field := current.Field(path[0])
+		if len(path) == 1 && field.Kind() == reflect.Pointer && field.IsNil() {
+			// Unset pointer field, let the callback decide whether to allocate
+			// a value for it, e.g. an optional param which was sent.
+			f(field, v)
+			return
+		}
+		r.every(reflect.Indirect(field), path[1:], v, f)
 	case reflect.Slice:
 		for j := 0; j < current.Len(); j++ {
 			r.every(reflect.Indirect(current.Index(j)), path, v, f)
@@ -445,6 +601,26 @@ func getHint(parent reflect.Type, name string, other string) string {
 	}
 }
 
+// formatContentTypes returns the full content types supported by the API's
+// formats, e.g. `application/json` and `application/cbor`, with the default
+// format first. Suffix-only keys like `json` are skipped.
+func formatContentTypes(config Config) []string {
+	types := make([]string, 0, len(config.Formats))
+	for ct := range config.Formats {
+		if strings.Contains(ct, "/") && ct != config.DefaultFormat {
+			types = append(types, ct)
+		}
+	}
+	sort.Strings(types)
+	if _, ok := config.Formats[config.DefaultFormat]; ok && strings.Contains(config.DefaultFormat, "/") {
+		types = append([]string{config.DefaultFormat}, types...)
+	}
+	if len(types) == 0 {
+		types = append(types, "application/json")
+	}
+	return types
+}
+
 type validateDeps struct {
 	pb  *PathBuffer
 	res *ValidateResult
@@ -489,6 +665,117 @@ func transformAndWrite(api API, ctx Context, status int, ct string, body any) {
 	}
 }
 
+// decodeBody wraps the request body reader to transparently decode it using
+// the request's `Content-Encoding`. If the encoding is unsupported or the body
+// cannot be decoded, an error response is written and false is returned.
+func decodeBody(api API, decoders map[string]ContentDecoder, ctx Context, reader io.Reader, res *ValidateResult) (io.ReadCloser, bool) {
+	encoding := ctx.Header("Content-Encoding")
+	if encoding == "" {
+		return io.NopCloser(reader), true
+	}
+	src := &sourceReader{r: reader}
+	decoded, err := decodeContent(decoders, encoding, src)
+	if err != nil {
+		if errors.Is(err, ErrUnknownContentEncoding) {
+			WriteErr(api, ctx, http.StatusUnsupportedMediaType, err.Error(), res.Errors...)
+			return nil, false
+		}
+		WriteErr(api, ctx, http.StatusBadRequest, "cannot decode request body", append(res.Errors, err)...)
+		return nil, false
+	}
+	return &decodedReader{ReadCloser: decoded, src: src}, true
+}
+
+// decodeError is returned when reading a request body fails because the
+// client sent data which cannot be decoded, e.g. a truncated gzip stream,
+// rather than because reading from the connection failed.
+type decodeError struct {
+	err error
+}
+
+func (e *decodeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *decodeError) Unwrap() error {
+	return e.err
+}
+
+// sourceReader remembers the last error from the raw request body, so that
+// errors from the connection can be told apart from decoding errors.
+type sourceReader struct {
+	r   io.Reader
+	err error
+}
+
+func (s *sourceReader) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	s.err = err
+	return n, err
+}
+
+// decodedReader returns errors from the content decoders as `decodeError`s.
+type decodedReader struct {
+	io.ReadCloser
+	src *sourceReader
+}
+
+func (d *decodedReader) Read(p []byte) (int, error) {
+	n, err := d.ReadCloser.Read(p)
+	if err != nil && err != io.EOF && err != d.src.err {
+		err = &decodeError{err: err}
+	}
+	return n, err
+}
+
+// bodyStream wraps a request body reader that is passed directly to the
+// handler via a `RawBody io.Reader` input field. It enforces the operation's
+// max body size, if one was explicitly set, and converts errors into `StatusError`s, so handlers can
+// return them as-is to send an HTTP 413 or 408 response. The body read
+// timeout is an idle timeout which is extended on every read, so long
+// uploads aren't cut off as long as data keeps arriving.
+type bodyStream struct {
+	r       io.Reader
+	limit   int64
+	read    int64
+	ctx     Context
+	timeout time.Duration
+}
+
+func (b *bodyStream) Read(p []byte) (int, error) {
+	if b.timeout > 0 {
+		b.ctx.SetReadDeadline(time.Now().Add(b.timeout))
+	}
+	if b.limit > 0 {
+		if b.read > b.limit {
+			return 0, b.tooLarge()
+		}
+		// Read one byte past the limit to detect bodies which are too large.
+		if remaining := b.limit - b.read + 1; int64(len(p)) > remaining {
+			p = p[:remaining]
+		}
+	}
+	n, err := b.r.Read(p)
+	b.read += int64(n)
+	if b.limit > 0 && b.read > b.limit {
+		return n - int(b.read-b.limit), b.tooLarge()
+	}
+	if err != nil && err != io.EOF {
+		if e, ok := err.(net.Error); ok && e.Timeout() {
+			return n, NewError(http.StatusRequestTimeout, "request body read timeout")
+		}
+		var de *decodeError
+		if errors.As(err, &de) {
+			return n, NewError(http.StatusBadRequest, "cannot decode request body", de.err)
+		}
+	}
+	return n, err
+}
+
+func (b *bodyStream) tooLarge() error {
+	return NewError(http.StatusRequestEntityTooLarge, fmt.Sprintf("request body is too large limit=%d bytes", b.limit))
+}
+
 func parseArrElement[T any](values []string, parse func(string) (T, error)) ([]T, error) {
 	result := make([]T, 0, len(values))
 
@@ -522,8 +809,11 @@ func writeHeader(write func(string, string), info *headerInfo, f reflect.Value)
 	case reflect.Bool:
 		write(info.Name, strconv.FormatBool(f.Bool()))
 	default:
-		if f.Type() == timeType && !f.Interface().(time.Time).IsZero() {
-			write(info.Name, f.Interface().(time.Time).Format(info.TimeFormat))
+		if f.Type() == timeType {
+			if t := f.Interface().(time.Time); !t.IsZero() {
+				write(info.Name, t.Format(info.TimeFormat))
+			}
+			// Don't set headers for unknown (zero) times.
 			return
 		}
 
@@ -564,6 +854,10 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 	oapi := api.OpenAPI()
 	registry := oapi.Components.Schemas
 
+	if m, ok := api.(OperationModifier); ok {
+		m.ModifyOperation(&op)
+	}
+
 	if op.Method == "" || op.Path == "" {
 		panic("method and path must be specified in operation")
 	}
@@ -573,6 +867,8 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 		panic("input must be a struct")
 	}
 	inputParams := findParams(registry, &op, inputType)
+	config := configOf(api)
+	formatTypes := formatContentTypes(config)
 	inputBodyIndex := make([]int, 0)
 	hasInputBody := false
 	if f, ok := inputType.FieldByName("Body"); ok {
@@ -584,9 +880,9 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 				required = true
 			}
 
-			contentType := "application/json"
+			contentTypes := formatTypes
 			if c := f.Tag.Get("contentType"); c != "" {
-				contentType = c
+				contentTypes = []string{c}
 			}
 			hint := getHint(inputType, f.Name, op.OperationID+"Request")
 			if nameHint := f.Tag.Get("nameHint"); nameHint != "" {
@@ -596,11 +892,12 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 
 			op.RequestBody = &RequestBody{
 				Required: required,
-				Content: map[string]*MediaType{
-					contentType: {
-						Schema: s,
-					},
-				},
+				Content:  make(map[string]*MediaType, len(contentTypes)),
+			}
+			for _, ct := range contentTypes {
+				op.RequestBody.Content[ct] = &MediaType{
+					Schema: s,
+				}
 			}
 		}
 
@@ -610,15 +907,24 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 		}
 
 		if op.MaxBodyBytes == 0 {
-			// 1 MB default
+			// 1 MB default. This only applies to buffered bodies, as streamed
+			// bodies can't be used with a `Body` field.
 			op.MaxBodyBytes = 1024 * 1024
 		}
 	}
 	rawBodyIndex := -1
 	rawBodyMultipart := false
 	rawBodyDecodedMultipart := false
+	rawBodyStream := false
+	rawBodyMultipartStream := false
 	if f, ok := inputType.FieldByName("RawBody"); ok {
 		rawBodyIndex = f.Index[0]
+		if f.Type == readerType {
+			if hasInputBody {
+				panic("RawBody io.Reader cannot be used with a Body field")
+			}
+			rawBodyStream = true
+		}
 		if op.RequestBody == nil {
 			op.RequestBody = &RequestBody{
 				Required: true,
@@ -636,6 +942,13 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 			contentType = "multipart/form-data"
 			rawBodyDecodedMultipart = true
 		}
+		if isMultipartFormStream(f.Type) {
+			if hasInputBody {
+				panic("RawBody MultipartFormStream cannot be used with a Body field")
+			}
+			contentType = "multipart/form-data"
+			rawBodyMultipartStream = true
+		}
 
 		if c := f.Tag.Get("contentType"); c != "" {
 			contentType = c
@@ -670,11 +983,18 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 					panic("Expected type MultipartFormFiles[T] to have a 'data *T' generic pointer field")
 				}
 				op.RequestBody.Content["multipart/form-data"] = &MediaType{
-					Schema:   multiPartFormFileSchema(dataField.Type.Elem()),
+					Schema:   multiPartFormFileSchema(registry, dataField.Type.Elem(), op.OperationID+"Request"),
 					Encoding: multiPartContentEncoding(dataField.Type.Elem()),
 				}
 				op.RequestBody.Required = false
 			}
+			if rawBodyMultipartStream {
+				formType := reflect.New(f.Type).Interface().(multipartStreamer).formType()
+				op.RequestBody.Content["multipart/form-data"] = &MediaType{
+					Schema:   multiPartFormFileSchema(registry, formType, op.OperationID+"Request"),
+					Encoding: multiPartContentEncoding(formType),
+				}
+			}
 		default:
 			op.RequestBody.Content[contentType] = &MediaType{
 				Schema: &Schema{
@@ -685,9 +1005,46 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 		}
 	}
 
+	// The body is validated against the same schema regardless of the format
+	// used to send it, so find the schema from one of the supported formats,
+	// falling back to any custom content type like `application/my-type+json`.
 	var inSchema *Schema
-	if op.RequestBody != nil && op.RequestBody.Content != nil && op.RequestBody.Content["application/json"] != nil && op.RequestBody.Content["application/json"].Schema != nil {
-		inSchema = op.RequestBody.Content["application/json"].Schema
+	decoders := config.ContentDecoders
+	if rawBodyStream || (rawBodyIndex != -1 && !hasInputBody && !rawBodyMultipart && !rawBodyDecodedMultipart && !rawBodyMultipartStream) {
+		// Raw bodies are passed to the handler exactly as sent, so handlers
+		// which check the `Content-Encoding` and decode the body themselves
+		// keep working.
+		decoders = nil
+	}
+
+	if hasInputBody && op.RequestBody != nil {
+		for _, ct := range formatTypes {
+			if mt := op.RequestBody.Content[ct]; mt != nil && mt.Schema != nil {
+				inSchema = mt.Schema
+				break
+			}
+		}
+		if inSchema == nil {
+			keys := make([]string, 0, len(op.RequestBody.Content))
+			for ct := range op.RequestBody.Content {
+				keys = append(keys, ct)
+			}
+			sort.Strings(keys)
+			for _, ct := range keys {
+				if mt := op.RequestBody.Content[ct]; mt != nil && mt.Schema != nil && mt.Schema.Format != "binary" {
+					inSchema = mt.Schema
+					break
+				}
+			}
+		}
+	}
+
+	// URL encoded form bodies are converted using the body's field names.
+	formBody := false
+	var formNames map[string]string
+	if hasInputBody && op.RequestBody != nil && op.RequestBody.Content[formContentType] != nil {
+		formBody = true
+		formNames = formFieldNames(inputType.FieldByIndex(inputBodyIndex).Type)
 	}
 
 	resolvers := findResolvers(resolverType, inputType)
@@ -702,18 +1059,30 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 	}
 
 	outStatusIndex := -1
+	var outStatuses []int
 	if f, ok := outputType.FieldByName("Status"); ok {
 		outStatusIndex = f.Index[0]
 		if f.Type.Kind() != reflect.Int {
 			panic("status field must be an int")
 		}
-		// TODO: enum tag?
-		// TODO: register each of the possible responses with the right model
-		//       and headers down below.
+		if enum := f.Tag.Get("enum"); enum != "" {
+			// Each possible status is documented as its own response.
+			for _, e := range strings.Split(enum, ",") {
+				code, err := strconv.Atoi(strings.TrimSpace(e))
+				if err != nil {
+					panic(fmt.Errorf("invalid status enum value '%s': %w", e, err))
+				}
+				outStatuses = append(outStatuses, code)
+			}
+			if op.DefaultStatus == 0 {
+				op.DefaultStatus = outStatuses[0]
+			}
+		}
 	}
 	outHeaders := findHeaders(outputType)
 	outBodyIndex := -1
 	outBodyFunc := false
+	var outSchema *Schema
 	if f, ok := outputType.FieldByName("Body"); ok {
 		outBodyIndex = f.Index[0]
 		if f.Type.Kind() == reflect.Func {
@@ -722,36 +1091,12 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 			if f.Type != bodyCallbackType {
 				panic("body field must be a function with signature func(huma.Context)")
 			}
-		}
-		status := op.DefaultStatus
-		if status == 0 {
-			status = http.StatusOK
-		}
-		statusStr := strconv.Itoa(status)
-		if op.Responses[statusStr] == nil {
-			op.Responses[statusStr] = &Response{}
-		}
-		if op.Responses[statusStr].Description == "" {
-			op.Responses[statusStr].Description = http.StatusText(status)
-		}
-		if op.Responses[statusStr].Headers == nil {
-			op.Responses[statusStr].Headers = map[string]*Param{}
-		}
-		if !outBodyFunc {
+		} else {
 			hint := getHint(outputType, f.Name, op.OperationID+"Response")
 			if nameHint := f.Tag.Get("nameHint"); nameHint != "" {
 				hint = nameHint
 			}
-			outSchema := SchemaFromField(registry, f, hint)
-			if op.Responses[statusStr].Content == nil {
-				op.Responses[statusStr].Content = map[string]*MediaType{}
-			}
-			if len(op.Responses[statusStr].Content) == 0 {
-				op.Responses[statusStr].Content["application/json"] = &MediaType{}
-			}
-			if op.Responses[statusStr].Content["application/json"] != nil && op.Responses[statusStr].Content["application/json"].Schema == nil {
-				op.Responses[statusStr].Content["application/json"].Schema = outSchema
-			}
+			outSchema = SchemaFromField(registry, f, hint)
 		}
 	}
 	if op.DefaultStatus == 0 {
@@ -762,16 +1107,14 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 		}
 	}
 	defaultStatusStr := strconv.Itoa(op.DefaultStatus)
-	if op.Responses[defaultStatusStr] == nil {
-		op.Responses[defaultStatusStr] = &Response{
-			Description: http.StatusText(op.DefaultStatus),
-		}
+	if !slicesContains(outStatuses, op.DefaultStatus) {
+		outStatuses = append([]int{op.DefaultStatus}, outStatuses...)
 	}
+
+	// Document the headers' names and types once, as they are shared by all
+	// of the possible responses.
+	outHeaderParams := map[string]*Param{}
 	for _, entry := range outHeaders.Paths {
-		// Document the header's name and type.
-		if op.Responses[defaultStatusStr].Headers == nil {
-			op.Responses[defaultStatusStr].Headers = map[string]*Param{}
-		}
 		v := entry.Value
 		f := v.Field
 		if f.Type.Kind() == reflect.Slice {
@@ -782,13 +1125,75 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 			// `.String()` on the value.
 			f.Type = stringType
 		}
-		op.Responses[defaultStatusStr].Headers[v.Name] = &Header{
+		outHeaderParams[v.Name] = &Header{
 			// We need to generate the schema from the field to get validation info
 			// like min/max and enums. Useful to let the client know possible values.
 			Schema: SchemaFromField(registry, f, getHint(outputType, f.Name, op.OperationID+defaultStatusStr+v.Name)),
 		}
 	}
 
+	// The output may only set the statuses of its own responses and those
+	// documented by the operation itself, not the error responses added below.
+	strictStatuses := map[int]bool{}
+	if op.StrictStatus || config.StrictStatus {
+		for _, status := range outStatuses {
+			strictStatuses[status] = true
+		}
+		for code := range op.Responses {
+			if status, err := strconv.Atoi(code); err == nil {
+				strictStatuses[status] = true
+			}
+		}
+	}
+
+	for _, status := range outStatuses {
+		statusStr := strconv.Itoa(status)
+		if op.Responses[statusStr] == nil {
+			op.Responses[statusStr] = &Response{}
+		}
+		resp := op.Responses[statusStr]
+		if resp.Description == "" {
+			resp.Description = http.StatusText(status)
+		}
+		if outBodyIndex != -1 && resp.Headers == nil {
+			resp.Headers = map[string]*Param{}
+		}
+		if outSchema != nil {
+			if resp.Content == nil {
+				resp.Content = map[string]*MediaType{}
+			}
+			if len(resp.Content) == 0 {
+				for _, ct := range formatTypes {
+					resp.Content[ct] = &MediaType{}
+				}
+			}
+			for _, ct := range formatTypes {
+				if resp.Content[ct] != nil && resp.Content[ct].Schema == nil {
+					resp.Content[ct].Schema = outSchema
+				}
+			}
+		}
+		for name, header := range outHeaderParams {
+			if resp.Headers == nil {
+				resp.Headers = map[string]*Param{}
+			}
+			resp.Headers[name] = header
+		}
+	}
+
+	if len(op.ErrorModels) > 0 {
+		codes := make([]int, 0, len(op.ErrorModels))
+		for code := range op.ErrorModels {
+			codes = append(codes, code)
+		}
+		sort.Ints(codes)
+		for _, code := range codes {
+			if !slicesContains(op.Errors, code) {
+				op.Errors = append(op.Errors, code)
+			}
+		}
+	}
+
 	if len(op.Errors) > 0 && (len(inputParams.Paths) > 0 || hasInputBody) {
 		op.Errors = append(op.Errors, http.StatusUnprocessableEntity)
 	}
@@ -796,39 +1201,35 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 		op.Errors = append(op.Errors, http.StatusInternalServerError)
 	}
 
-	exampleErr := NewError(0, "")
-	errContentType := "application/json"
-	if ctf, ok := exampleErr.(ContentTypeFilter); ok {
-		errContentType = ctf.ContentType(errContentType)
-	}
-	errType := deref(reflect.TypeOf(exampleErr))
-	errSchema := registry.Schema(errType, true, getHint(errType, "", "Error"))
 	for _, code := range op.Errors {
-		op.Responses[strconv.Itoa(code)] = &Response{
-			Description: http.StatusText(code),
-			Content: map[string]*MediaType{
-				errContentType: {
-					Schema: errSchema,
-				},
-			},
-		}
+		op.Responses[strconv.Itoa(code)] = ErrorResponse(registry, &op, code)
 	}
-	if len(op.Responses) <= 1 && len(op.Errors) == 0 {
+	if len(op.Responses) <= len(outStatuses) && len(op.Errors) == 0 {
 		// No errors are defined, so set a default response.
-		op.Responses["default"] = &Response{
-			Description: "Error",
-			Content: map[string]*MediaType{
-				errContentType: {
-					Schema: errSchema,
-				},
-			},
-		}
+		op.Responses["default"] = ErrorResponse(registry, nil, 0)
+		op.Responses["default"].Description = "Error"
 	}
 
 	if !op.Hidden {
 		oapi.AddOperation(&op)
 	}
 
+	validateResponses := op.ValidateResponses
+	if validateResponses == nil {
+		validateResponses = config.ValidateResponses
+	}
+
+	// outputStatus returns the response status set by the handler's output.
+	outputStatus := func(vo reflect.Value) int {
+		if outStatusIndex != -1 {
+			// An unset status means the default status.
+			if status := int(vo.Field(outStatusIndex).Int()); status != 0 {
+				return status
+			}
+		}
+		return op.DefaultStatus
+	}
+
 	a := api.Adapter()
 
 	a.Handle(&op, api.Middlewares().Handler(op.Middlewares.Handler(func(ctx Context) {
@@ -850,14 +1251,41 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 
 		v := reflect.ValueOf(&input).Elem()
 		inputParams.Every(v, func(f reflect.Value, p *paramFieldInfo) {
+			if p.Style == styleDeepObject {
+				setDeepObjectParam(oapi.Components.Schemas, ctx, &op, f, p, pb, res)
+				return
+			}
+
 			var value string
+			var values []string
 			switch p.Loc {
 			case "path":
 				value = ctx.Param(p.Name)
 			case "query":
-				value = ctx.Query(p.Name)
+				if p.Explode {
+					// Repeated keys like `?tag=a&tag=b`, where each value is an item.
+					u := ctx.URL()
+					queryparam.Each(u.RawQuery, p.Name, func(v string) {
+						values = append(values, v)
+					})
+					value = strings.Join(values, ",")
+				} else {
+					value = ctx.Query(p.Name)
+				}
 			case "header":
-				value = ctx.Header(p.Name)
+				if p.Type.Kind() == reflect.Slice {
+					// Repeated headers are equivalent to a single comma-separated
+					// header, see RFC 9110 section 5.3.
+					ctx.EachHeader(func(name, v string) {
+						if strings.EqualFold(name, p.Name) {
+							values = append(values, v)
+						}
+					})
+					value = strings.Join(values, ",")
+					values = nil
+				} else {
+					value = ctx.Header(p.Name)
+				}
 			case "cookie":
 				if cookies == nil {
 					// Only parse the cookie headers once, on-demand.
@@ -869,8 +1297,12 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 				if c, ok := cookies[p.Name]; ok {
 					// Special case: http.Cookie type, meaning we want the entire parsed
 					// cookie struct, not just the value.
-					if f.Type() == cookieType {
-						f.Set(reflect.ValueOf(cookies[p.Name]).Elem())
+					if p.Type == cookieType {
+						if p.Pointer {
+							f.Set(reflect.ValueOf(c))
+						} else {
+							f.Set(reflect.ValueOf(c).Elem())
+						}
 						return
 					}
 
@@ -895,6 +1327,26 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 			if value != "" {
 				var pv any
 
+				if p.Pointer {
+					// The param is present, so allocate a value to parse into.
+					ptr := reflect.New(p.Type)
+					f.Set(ptr)
+					f = ptr.Elem()
+				}
+
+				if p.Unmarshaler {
+					// Custom type which knows how to parse itself.
+					pv, err := unmarshalParam(f, p, value, values)
+					if err != nil {
+						res.Add(pb, value, "invalid value: "+err.Error())
+						return
+					}
+					if !op.SkipValidateParams {
+						Validate(oapi.Components.Schemas, p.Schema, pb, ModeWriteToServer, pv, res)
+					}
+					return
+				}
+
 				switch p.Type.Kind() {
 				case reflect.String:
 					f.SetString(value)
@@ -933,15 +1385,17 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 					pv = v
 				default:
 					if f.Type().Kind() == reflect.Slice {
+						if values == nil {
+							values = strings.Split(value, ",")
+						}
+
 						switch f.Type().Elem().Kind() {
 
 						case reflect.String:
-							values := strings.Split(value, ",")
 							f.Set(reflect.ValueOf(values))
 							pv = values
 
 						case reflect.Int:
-							values := strings.Split(value, ",")
 							vs, err := parseArrElement(values, func(s string) (int, error) {
 								val, err := strconv.ParseInt(s, 10, strconv.IntSize)
 								if err != nil {
@@ -957,7 +1411,6 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 							pv = vs
 
 						case reflect.Int8:
-							values := strings.Split(value, ",")
 							vs, err := parseArrElement(values, func(s string) (int8, error) {
 								val, err := strconv.ParseInt(s, 10, 8)
 								if err != nil {
@@ -973,7 +1426,6 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 							pv = vs
 
 						case reflect.Int16:
-							values := strings.Split(value, ",")
 							vs, err := parseArrElement(values, func(s string) (int16, error) {
 								val, err := strconv.ParseInt(s, 10, 16)
 								if err != nil {
@@ -989,7 +1441,6 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 							pv = vs
 
 						case reflect.Int32:
-							values := strings.Split(value, ",")
 							vs, err := parseArrElement(values, func(s string) (int32, error) {
 								val, err := strconv.ParseInt(s, 10, 32)
 								if err != nil {
@@ -1005,7 +1456,6 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 							pv = vs
 
 						case reflect.Int64:
-							values := strings.Split(value, ",")
 							vs, err := parseArrElement(values, func(s string) (int64, error) {
 								val, err := strconv.ParseInt(s, 10, 64)
 								if err != nil {
@@ -1021,7 +1471,6 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 							pv = vs
 
 						case reflect.Uint:
-							values := strings.Split(value, ",")
 							vs, err := parseArrElement(values, func(s string) (uint, error) {
 								val, err := strconv.ParseUint(s, 10, strconv.IntSize)
 								if err != nil {
@@ -1037,7 +1486,6 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 							pv = vs
 
 						case reflect.Uint16:
-							values := strings.Split(value, ",")
 							vs, err := parseArrElement(values, func(s string) (uint16, error) {
 								val, err := strconv.ParseUint(s, 10, 16)
 								if err != nil {
@@ -1053,7 +1501,6 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 							pv = vs
 
 						case reflect.Uint32:
-							values := strings.Split(value, ",")
 							vs, err := parseArrElement(values, func(s string) (uint32, error) {
 								val, err := strconv.ParseUint(s, 10, 32)
 								if err != nil {
@@ -1069,7 +1516,6 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 							pv = vs
 
 						case reflect.Uint64:
-							values := strings.Split(value, ",")
 							vs, err := parseArrElement(values, func(s string) (uint64, error) {
 								val, err := strconv.ParseUint(s, 10, 64)
 								if err != nil {
@@ -1085,7 +1531,6 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 							pv = vs
 
 						case reflect.Float32:
-							values := strings.Split(value, ",")
 							vs, err := parseArrElement(values, func(s string) (float32, error) {
 								val, err := strconv.ParseFloat(s, 32)
 								if err != nil {
@@ -1101,7 +1546,6 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 							pv = vs
 
 						case reflect.Float64:
-							values := strings.Split(value, ",")
 							vs, err := parseArrElement(values, func(s string) (float64, error) {
 								val, err := strconv.ParseFloat(s, 64)
 								if err != nil {
@@ -1131,16 +1575,6 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 						break
 					}
 
-					// Last resort: use the `encoding.TextUnmarshaler` interface.
-					if fn, ok := f.Addr().Interface().(encoding.TextUnmarshaler); ok {
-						if err := fn.UnmarshalText([]byte(value)); err != nil {
-							res.Add(pb, value, "invalid value: "+err.Error())
-							return
-						}
-						pv = value
-						break
-					}
-
 					panic("unsupported param type " + p.Type.String())
 				}
 
@@ -1159,7 +1593,46 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 				ctx.SetReadDeadline(time.Time{})
 			}
 
-			if rawBodyMultipart || rawBodyDecodedMultipart {
+			if rawBodyStream {
+				// Hand the live body reader to the handler without buffering it.
+				reader := ctx.BodyReader()
+				if reader == nil {
+					reader = bytes.NewReader(nil)
+				}
+				if closer, ok := reader.(io.Closer); ok {
+					defer closer.Close()
+				}
+				decoded, ok := decodeBody(api, decoders, ctx, reader, res)
+				if !ok {
+					return
+				}
+				defer decoded.Close()
+				v.Field(rawBodyIndex).Set(reflect.ValueOf(&bodyStream{
+					r:       decoded,
+					limit:   op.MaxBodyBytes,
+					ctx:     ctx,
+					timeout: op.BodyReadTimeout,
+				}))
+			} else if rawBodyMultipartStream {
+				// Parts are read one at a time by the handler.
+				reader := ctx.BodyReader()
+				if reader == nil {
+					reader = bytes.NewReader(nil)
+				}
+				if closer, ok := reader.(io.Closer); ok {
+					defer closer.Close()
+				}
+				decoded, ok := decodeBody(api, decoders, ctx, reader, res)
+				if !ok {
+					return
+				}
+				defer decoded.Close()
+				v.Field(rawBodyIndex).Addr().Interface().(multipartStreamer).setup(
+					&bodyStream{r: decoded, limit: op.MaxBodyBytes, ctx: ctx, timeout: op.BodyReadTimeout},
+					ctx.Header("Content-Type"),
+					op.RequestBody.Content["multipart/form-data"],
+				)
+			} else if rawBodyMultipart || rawBodyDecodedMultipart {
 				form, err := ctx.GetMultipartForm()
 				if err != nil || form == nil {
 					res.Errors = append(res.Errors, &ErrorDetail{
@@ -1172,12 +1645,10 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 						f.Set(reflect.ValueOf(*form))
 					} else {
 						f.FieldByName("Form").Set(reflect.ValueOf(form))
-						r := f.Addr().
-							MethodByName("Decode").
-							Call([]reflect.Value{
-								reflect.ValueOf(op.RequestBody.Content["multipart/form-data"]),
-							})
-						errs := r[0].Interface().([]error)
+						errs := f.Addr().Interface().(multipartFormDecoder).decode(
+							registry,
+							op.RequestBody.Content["multipart/form-data"],
+						)
 						if errs != nil {
 							WriteErr(api, ctx, http.StatusUnprocessableEntity, "validation failed", errs...)
 							return
@@ -1193,7 +1664,16 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 				if closer, ok := reader.(io.Closer); ok {
 					defer closer.Close()
 				}
+				decoded, ok := decodeBody(api, decoders, ctx, reader, res)
+				if !ok {
+					buf.Reset()
+					bufPool.Put(buf)
+					return
+				}
+				defer decoded.Close()
+				reader = decoded
 				if op.MaxBodyBytes > 0 {
+					// The limit applies to the decoded body to prevent decompression bombs.
 					reader = io.LimitReader(reader, op.MaxBodyBytes)
 				}
 				count, err := io.Copy(buf, reader)
@@ -1214,6 +1694,13 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 						return
 					}
 
+					var de *decodeError
+					if errors.As(err, &de) {
+						// The client sent a corrupt body, e.g. a truncated gzip stream.
+						WriteErr(api, ctx, http.StatusBadRequest, "cannot decode request body", append(res.Errors, de.err)...)
+						return
+					}
+
 					WriteErr(api, ctx, http.StatusInternalServerError, "cannot read request body", err)
 					return
 				}
@@ -1224,6 +1711,20 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 					f.SetBytes(body)
 				}
 
+				contentType := ctx.Header("Content-Type")
+				if formBody && len(body) > 0 && isFormContentType(contentType) {
+					// Convert the form into JSON so it can be validated & parsed below.
+					converted, err := parseFormBody(oapi.Components.Schemas, inSchema, formNames, body)
+					if err != nil {
+						buf.Reset()
+						bufPool.Put(buf)
+						WriteErr(api, ctx, http.StatusBadRequest, "cannot parse form body", append(res.Errors, err)...)
+						return
+					}
+					body = converted
+					contentType = "application/json"
+				}
+
 				if len(body) == 0 {
 					if op.RequestBody != nil && op.RequestBody.Required {
 						buf.Reset()
@@ -1238,7 +1739,7 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 						// or equivalent, which can be easily validated. Then, convert to the
 						// expected struct type to call the handler.
 						var parsed any
-						if err := api.Unmarshal(ctx.Header("Content-Type"), body, &parsed); err != nil {
+						if err := api.Unmarshal(contentType, body, &parsed); err != nil {
 							errStatus = http.StatusBadRequest
 							if errors.Is(err, ErrUnknownContentType) {
 								errStatus = http.StatusUnsupportedMediaType
@@ -1271,7 +1772,7 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 						for _, index := range inputBodyIndex {
 							f = f.Field(index)
 						}
-						if err := api.Unmarshal(ctx.Header("Content-Type"), body, f.Addr().Interface()); err != nil {
+						if err := api.Unmarshal(contentType, body, f.Addr().Interface()); err != nil {
 							if parseErrCount == 0 {
 								// Hmm, this should have worked... validator missed something?
 								res.Errors = append(res.Errors, &ErrorDetail{
@@ -1325,6 +1826,13 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 		}
 
 		output, err := handler(ctx.Context(), &input)
+		if err == nil && validateResponses != nil {
+			vo := reflect.ValueOf(output).Elem()
+			status := outputStatus(vo)
+			if errs := validateOutput(registry, &op, formatTypes, status, vo, outHeaders, outBodyIndex); len(errs) > 0 {
+				err = validateResponses(ctx, status, errs)
+			}
+		}
 		if err != nil {
 			var he HeadersError
 			if errors.As(err, &he) {
@@ -1339,9 +1847,9 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 			var se StatusError
 			if errors.As(err, &se) {
 				status = se.GetStatus()
-				err = se
+				err = asOperationError(&op, se)
 			} else {
-				err = NewError(http.StatusInternalServerError, err.Error())
+				err = newOperationError(&op, http.StatusInternalServerError, err.Error())
 			}
 
 			ct, _ := api.Negotiate(ctx.Header("Accept"))
@@ -1354,10 +1862,20 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 			return
 		}
 
+		vo := reflect.ValueOf(output).Elem()
+		status := outputStatus(vo)
+		if len(strictStatuses) > 0 && !strictStatuses[status] {
+			WriteErr(api, ctx, http.StatusInternalServerError, fmt.Sprintf("undocumented response status %d", status))
+			return
+		}
+
 		// Serialize output headers
 		ct := ""
-		vo := reflect.ValueOf(output).Elem()
 		outHeaders.Every(vo, func(f reflect.Value, info *headerInfo) {
+			if f.Kind() == reflect.Pointer {
+				// Unset optional header, nothing to write.
+				return
+			}
 			if f.Kind() == reflect.Slice {
 				for i := 0; i < f.Len(); i++ {
 					writeHeader(ctx.AppendHeader, info, f.Index(i))
@@ -1371,11 +1889,6 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 			}
 		})
 
-		status := op.DefaultStatus
-		if outStatusIndex != -1 {
-			status = int(vo.Field(outStatusIndex).Int())
-		}
-
 		if outBodyIndex != -1 {
 			// Serialize output body
 			body := vo.Field(outBodyIndex).Interface()
@@ -1513,9 +2026,14 @@ func OperationTags(tags ...string) func(o *Operation) {
 
 func convenience[I, O any](api API, method, path string, handler func(context.Context, *I) (*O, error), operationHandlers ...func(o *Operation)) {
 	var o *O
+	fullPath := path
+	if g, ok := api.(*Group); ok {
+		// Include the group prefix so IDs are unique across groups.
+		fullPath = g.Prefix() + path
+	}
 	operation := Operation{
-		OperationID: GenerateOperationID(method, path, o),
-		Summary:     GenerateSummary(method, path, o),
+		OperationID: GenerateOperationID(method, fullPath, o),
+		Summary:     GenerateSummary(method, fullPath, o),
 		Method:      method,
 		Path:        path,
 	}
@@ -1,6 +1,8 @@
 package huma_test
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"errors"
@@ -11,7 +13,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/http/httputil"
+	"net/netip"
 	"os"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -61,7 +65,51 @@ func (UUID) Schema(r huma.Registry) *huma.Schema {
 	return &huma.Schema{Type: huma.TypeString, Format: "uuid"}
 }
 
+// OrgID is a custom type for testing ParamUnmarshaler
+type OrgID struct {
+	Prefix string
+	ID     int
+}
+
+func (o *OrgID) UnmarshalParam(value string) error {
+	prefix, id, ok := strings.Cut(value, "-")
+	if !ok {
+		return errors.New("missing separator")
+	}
+	n, err := strconv.Atoi(id)
+	o.Prefix, o.ID = prefix, n
+	return err
+}
+
+func (o OrgID) Schema(r huma.Registry) *huma.Schema {
+	return &huma.Schema{Type: huma.TypeString, Pattern: "^[a-z]+-[0-9]+$"}
+}
+
+// Color is a custom enum type for testing encoding.TextUnmarshaler params
+type Color int
+
+func (c *Color) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "red":
+		*c = 1
+	case "blue":
+		*c = 2
+	default:
+		return errors.New("unknown color")
+	}
+	return nil
+}
+
 // BodyContainer is an embed request body struct to test request body unmarshalling
+// gzipString returns the gzip compressed version of `s`.
+func gzipString(s string) string {
+	buf := bytes.Buffer{}
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte(s))
+	gz.Close()
+	return buf.String()
+}
+
 type BodyContainer struct {
 	Body struct {
 		Name string `json:"name"`
@@ -477,6 +525,311 @@ func TestFeatures(t *testing.T) {
 				assert.Contains(t, resp.Body.String(), "query.floats64")
 			},
 		},
+		{
+			Name: "params-pointer",
+			Register: func(t *testing.T, api huma.API) {
+				huma.Register(api, huma.Operation{
+					Method: http.MethodGet,
+					Path:   "/test-params-pointer",
+				}, func(ctx context.Context, input *struct {
+					QueryLimit   *int         `query:"limit"`
+					QueryMissing *int         `query:"missing"`
+					QueryString  *string      `query:"string"`
+					QueryBool    *bool        `query:"bool"`
+					QueryBefore  *time.Time   `query:"before"`
+					QueryInts    *[]int       `query:"ints"`
+					QueryAbsent  *[]string    `query:"absent"`
+					HeaderString *string      `header:"String"`
+					CookieValue  *string      `cookie:"one"`
+					CookieFull   *http.Cookie `cookie:"two"`
+					CookieNone   *http.Cookie `cookie:"three"`
+				}) (*struct{}, error) {
+					require.NotNil(t, input.QueryLimit)
+					assert.Equal(t, 0, *input.QueryLimit)
+					assert.Nil(t, input.QueryMissing)
+					require.NotNil(t, input.QueryString)
+					assert.Equal(t, "foo", *input.QueryString)
+					require.NotNil(t, input.QueryBool)
+					assert.False(t, *input.QueryBool)
+					require.NotNil(t, input.QueryBefore)
+					assert.True(t, input.QueryBefore.Equal(time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)))
+					require.NotNil(t, input.QueryInts)
+					assert.Equal(t, []int{1, 2}, *input.QueryInts)
+					assert.Nil(t, input.QueryAbsent)
+					require.NotNil(t, input.HeaderString)
+					assert.Equal(t, "baz", *input.HeaderString)
+					require.NotNil(t, input.CookieValue)
+					assert.Equal(t, "foo", *input.CookieValue)
+					require.NotNil(t, input.CookieFull)
+					assert.Equal(t, "bar", input.CookieFull.Value)
+					assert.Nil(t, input.CookieNone)
+					return nil, nil
+				})
+
+				// Pointer params are optional.
+				for _, param := range api.OpenAPI().Paths["/test-params-pointer"].Get.Parameters {
+					assert.False(t, param.Required, param.Name)
+				}
+				assert.Equal(t, "integer", api.OpenAPI().Paths["/test-params-pointer"].Get.Parameters[0].Schema.Type)
+			},
+			Method: http.MethodGet,
+			URL:    "/test-params-pointer?limit=0&string=foo&bool=false&before=2023-01-01T12:00:00Z&ints=1,2",
+			Headers: map[string]string{
+				"string": "baz",
+				"cookie": "one=foo; two=bar",
+			},
+		},
+		{
+			Name: "params-pointer-default",
+			Register: func(t *testing.T, api huma.API) {
+				huma.Register(api, huma.Operation{
+					Method: http.MethodPut,
+					Path:   "/test-params-pointer-default",
+				}, func(ctx context.Context, input *struct {
+					Limit  *int    `query:"limit" default:"10"`
+					Offset *int    `query:"offset" default:"5"`
+					Count  int     `query:"count" default:"3"`
+					Sort   *string `header:"Sort" default:"name"`
+					Body   struct {
+						Name string `json:"name,omitempty" default:"anon"`
+					}
+				}) (*struct{}, error) {
+					require.NotNil(t, input.Limit)
+					assert.Equal(t, 10, *input.Limit)
+					require.NotNil(t, input.Offset)
+					assert.Equal(t, 0, *input.Offset)
+					assert.Equal(t, 0, input.Count)
+					require.NotNil(t, input.Sort)
+					assert.Equal(t, "name", *input.Sort)
+					assert.Equal(t, "anon", input.Body.Name)
+					return nil, nil
+				})
+
+				params := api.OpenAPI().Paths["/test-params-pointer-default"].Put.Parameters
+				assert.Equal(t, 10, params[0].Schema.Default)
+				assert.False(t, params[0].Required)
+			},
+			Method: http.MethodPut,
+			URL:    "/test-params-pointer-default?offset=0&count=0",
+			Body:   `{}`,
+		},
+		{
+			Name: "params-pointer-error",
+			Register: func(t *testing.T, api huma.API) {
+				huma.Register(api, huma.Operation{
+					Method: http.MethodGet,
+					Path:   "/test-params-pointer",
+				}, func(ctx context.Context, input *struct {
+					QueryLimit *int `query:"limit" minimum:"1"`
+					QueryReq   *int `query:"req" required:"true"`
+				}) (*struct{}, error) {
+					return nil, nil
+				})
+			},
+			Method: http.MethodGet,
+			URL:    "/test-params-pointer?limit=0",
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+				assert.Contains(t, resp.Body.String(), "expected number \\u003e= 1")
+				assert.Contains(t, resp.Body.String(), "required query parameter is missing")
+			},
+		},
+		{
+			Name: "params-explode",
+			Register: func(t *testing.T, api huma.API) {
+				huma.Register(api, huma.Operation{
+					Method: http.MethodGet,
+					Path:   "/test-params-explode",
+				}, func(ctx context.Context, input *struct {
+					QueryTags    []string  `query:"tag" explode:"true"`
+					QueryInts    []int     `query:"int" explode:"true"`
+					QueryPtr     *[]string `query:"ptr" explode:"true"`
+					QueryDefault []string  `query:"def" explode:"true" default:"a,b"`
+					QueryCSV     []string  `query:"csv"`
+				}) (*struct{}, error) {
+					assert.Equal(t, []string{"a,b", "c"}, input.QueryTags)
+					assert.Equal(t, []int{1, 2, 3}, input.QueryInts)
+					assert.Nil(t, input.QueryPtr)
+					assert.Equal(t, []string{"a", "b"}, input.QueryDefault)
+					assert.Equal(t, []string{"a", "b"}, input.QueryCSV)
+					return nil, nil
+				})
+
+				params := api.OpenAPI().Paths["/test-params-explode"].Get.Parameters
+				assert.True(t, *params[0].Explode)
+				assert.False(t, *params[4].Explode)
+			},
+			Method: http.MethodGet,
+			URL:    "/test-params-explode?tag=a%2Cb&int=1&tag=c&int=2&int=3&csv=a,b",
+		},
+		{
+			Name: "params-explode-error",
+			Register: func(t *testing.T, api huma.API) {
+				huma.Register(api, huma.Operation{
+					Method: http.MethodGet,
+					Path:   "/test-params-explode",
+				}, func(ctx context.Context, input *struct {
+					QueryInts []int `query:"int" explode:"true" maxItems:"2"`
+				}) (*struct{}, error) {
+					return nil, nil
+				})
+			},
+			Method: http.MethodGet,
+			URL:    "/test-params-explode?int=1&int=2&int=3",
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+				assert.Contains(t, resp.Body.String(), "expected array length")
+				assert.Contains(t, resp.Body.String(), "query.int")
+			},
+		},
+		{
+			Name: "params-deep-object",
+			Register: func(t *testing.T, api huma.API) {
+				type Owner struct {
+					Name string `json:"name"`
+					Age  int    `json:"age,omitempty"`
+				}
+				type Filter struct {
+					Status string   `json:"status" enum:"open,closed"`
+					Count  int      `json:"count,omitempty"`
+					Draft  bool     `json:"draft,omitempty"`
+					Labels []string `json:"labels,omitempty"`
+					Owner  *Owner   `json:"owner,omitempty"`
+				}
+				huma.Register(api, huma.Operation{
+					Method: http.MethodGet,
+					Path:   "/test-params-deep-object",
+				}, func(ctx context.Context, input *struct {
+					Filter  Filter            `query:"filter"`
+					Map     map[string]string `query:"map"`
+					Ints    map[string]int    `query:"ints"`
+					Missing *Filter           `query:"missing"`
+				}) (*struct{}, error) {
+					assert.Equal(t, Filter{
+						Status: "open",
+						Count:  5,
+						Draft:  true,
+						Labels: []string{"a", "b"},
+						Owner:  &Owner{Name: "bob", Age: 42},
+					}, input.Filter)
+					assert.Equal(t, map[string]string{"foo": "bar", "baz": "1"}, input.Map)
+					assert.Equal(t, map[string]int{"one": 1}, input.Ints)
+					assert.Nil(t, input.Missing)
+					return nil, nil
+				})
+
+				param := api.OpenAPI().Paths["/test-params-deep-object"].Get.Parameters[0]
+				assert.Equal(t, "deepObject", param.Style)
+				assert.True(t, *param.Explode)
+			},
+			Method: http.MethodGet,
+			URL:    "/test-params-deep-object?filter[status]=open&filter[count]=5&filter[draft]=true&filter[labels]=a,b&filter[owner][name]=bob&filter[owner][age]=42&map[foo]=bar&map[baz]=1&ints[one]=1",
+		},
+		{
+			Name: "params-deep-object-error",
+			Register: func(t *testing.T, api huma.API) {
+				huma.Register(api, huma.Operation{
+					Method: http.MethodGet,
+					Path:   "/test-params-deep-object",
+				}, func(ctx context.Context, input *struct {
+					Filter struct {
+						Status string `json:"status" enum:"open,closed"`
+						Count  int    `json:"count,omitempty" minimum:"1"`
+					} `query:"filter"`
+					Required map[string]string `query:"required" required:"true"`
+				}) (*struct{}, error) {
+					return nil, nil
+				})
+			},
+			Method: http.MethodGet,
+			URL:    "/test-params-deep-object?filter[status]=bad&filter[count]=0",
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+				assert.Contains(t, resp.Body.String(), "query.filter.status")
+				assert.Contains(t, resp.Body.String(), "query.filter.count")
+				assert.Contains(t, resp.Body.String(), "required query parameter is missing")
+			},
+		},
+		{
+			Name: "params-deep-object-conflict",
+			Register: func(t *testing.T, api huma.API) {
+				huma.Register(api, huma.Operation{
+					Method: http.MethodGet,
+					Path:   "/test-params-deep-object",
+				}, func(ctx context.Context, input *struct {
+					Filter map[string]any `query:"filter"`
+				}) (*struct{}, error) {
+					return nil, nil
+				})
+			},
+			Method: http.MethodGet,
+			URL:    "/test-params-deep-object?filter[a][b]=2&filter[a]=1",
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+				assert.Contains(t, resp.Body.String(), "conflicting keys: filter[a][b] is also used for a value")
+			},
+		},
+		{
+			Name: "params-unmarshaler",
+			Register: func(t *testing.T, api huma.API) {
+				huma.Register(api, huma.Operation{
+					Method: http.MethodGet,
+					Path:   "/test-params-unmarshaler/{org}",
+				}, func(ctx context.Context, input *struct {
+					PathOrg     OrgID      `path:"org"`
+					QueryOrgs   []OrgID    `query:"orgs"`
+					QueryAddr   netip.Addr `query:"addr"`
+					QueryColor  Color      `query:"color"`
+					QueryColors []Color    `query:"colors" explode:"true"`
+					HeaderColor *Color     `header:"Color"`
+				}) (*struct{}, error) {
+					assert.Equal(t, OrgID{Prefix: "acme", ID: 1}, input.PathOrg)
+					assert.Equal(t, []OrgID{{"a", 1}, {"b", 2}}, input.QueryOrgs)
+					assert.Equal(t, netip.MustParseAddr("127.0.0.1"), input.QueryAddr)
+					assert.Equal(t, Color(1), input.QueryColor)
+					assert.Equal(t, []Color{1, 2}, input.QueryColors)
+					require.NotNil(t, input.HeaderColor)
+					assert.Equal(t, Color(2), *input.HeaderColor)
+					return nil, nil
+				})
+
+				params := api.OpenAPI().Paths["/test-params-unmarshaler/{org}"].Get.Parameters
+				assert.Equal(t, "^[a-z]+-[0-9]+$", params[0].Schema.Pattern)
+				assert.Equal(t, "array", params[1].Schema.Type)
+				assert.Equal(t, "^[a-z]+-[0-9]+$", params[1].Schema.Items.Pattern)
+				assert.Equal(t, "ipv4", api.OpenAPI().Components.Schemas.SchemaFromRef(params[2].Schema.Ref).Format)
+				assert.Equal(t, "string", params[3].Schema.Type)
+				assert.Equal(t, "string", params[4].Schema.Items.Type)
+			},
+			Method: http.MethodGet,
+			URL:    "/test-params-unmarshaler/acme-1?orgs=a-1,b-2&addr=127.0.0.1&color=red&colors=red&colors=blue",
+			Headers: map[string]string{
+				"Color": "blue",
+			},
+		},
+		{
+			Name: "params-unmarshaler-error",
+			Register: func(t *testing.T, api huma.API) {
+				huma.Register(api, huma.Operation{
+					Method: http.MethodGet,
+					Path:   "/test-params-unmarshaler/{org}",
+				}, func(ctx context.Context, input *struct {
+					PathOrg    OrgID   `path:"org"`
+					QueryColor Color   `query:"color"`
+					QueryOrgs  []OrgID `query:"orgs"`
+				}) (*struct{}, error) {
+					return nil, nil
+				})
+			},
+			Method: http.MethodGet,
+			URL:    "/test-params-unmarshaler/BAD-1?color=green&orgs=a-1,b",
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+				assert.Contains(t, resp.Body.String(), `"location":"path.org"`)
+				assert.Contains(t, resp.Body.String(), `"message":"invalid value: unknown color","location":"query.color"`)
+				assert.Contains(t, resp.Body.String(), `"message":"invalid value: missing separator","location":"query.orgs"`)
+			},
+		},
 		{
 			Name: "param-unsupported-500",
 			Register: func(t *testing.T, api huma.API) {
@@ -738,121 +1091,391 @@ func TestFeatures(t *testing.T) {
 			},
 		},
 		{
-			Name: "request-body-file-upload",
+			Name: "request-body-stream",
 			Register: func(t *testing.T, api huma.API) {
 				huma.Register(api, huma.Operation{
-					Method: http.MethodPut,
-					Path:   "/file",
+					Method:       http.MethodPut,
+					Path:         "/upload",
+					MaxBodyBytes: 11,
 				}, func(ctx context.Context, input *struct {
-					RawBody []byte `contentType:"application/foo"`
+					RawBody io.Reader
 				}) (*struct{}, error) {
-					assert.Equal(t, `some-data`, string(input.RawBody))
+					b, err := io.ReadAll(input.RawBody)
+					require.NoError(t, err)
+					assert.Equal(t, "hello world", string(b))
 					return nil, nil
 				})
 
-				// Ensure OpenAPI spec is listed as a binary upload. This enables
-				// generated documentation to show a file upload button.
-				assert.Equal(t, "binary", api.OpenAPI().Paths["/file"].Put.RequestBody.Content["application/foo"].Schema.Format)
+				body := api.OpenAPI().Paths["/upload"].Put.RequestBody
+				assert.Equal(t, "binary", body.Content["application/octet-stream"].Schema.Format)
 			},
-			Method:  http.MethodPut,
-			URL:     "/file",
-			Headers: map[string]string{"Content-Type": "application/foo"},
-			Body:    `some-data`,
+			Method: http.MethodPut,
+			URL:    "/upload",
+			Body:   "hello world",
 		},
 		{
-			Name: "request-body-multipart-file-decoded",
+			Name: "request-body-stream-unlimited",
 			Register: func(t *testing.T, api huma.API) {
 				huma.Register(api, huma.Operation{
-					Method: http.MethodPost,
+					Method: http.MethodPut,
 					Path:   "/upload",
 				}, func(ctx context.Context, input *struct {
-					RawBody huma.MultipartFormFiles[struct {
-						HelloWorld   huma.FormFile   `form:"file" contentType:"text/plain" required:"true"`
-						Greetings    []huma.FormFile `form:"greetings" contentType:"text/plain" required:"true"`
-						NoTagBinding huma.FormFile   `contentType:"text/plain"`
-						UnusedField  string          // Ignored altogether
-					}]
+					RawBody io.Reader
 				}) (*struct{}, error) {
-					fileData := input.RawBody.Data()
-
-					assert.Equal(t, "text/plain", fileData.HelloWorld.ContentType)
-					assert.Equal(t, "test.txt", fileData.HelloWorld.Filename)
-					assert.Equal(t, len("Hello, World!"), int(fileData.HelloWorld.Size))
-					assert.True(t, fileData.HelloWorld.IsSet)
-					b, err := io.ReadAll(fileData.HelloWorld)
-					require.NoError(t, err)
-					assert.Equal(t, "Hello, World!", string(b))
-
-					assert.Equal(t, "text/plain", fileData.NoTagBinding.ContentType)
-					assert.True(t, fileData.NoTagBinding.IsSet)
-					b, err = io.ReadAll(fileData.NoTagBinding)
-					require.NoError(t, err)
-					assert.Equal(t, `Use struct field name as fallback when no "form" tag is provided.`, string(b))
-
-					expected := []string{"Hello", "World"}
-					for i, e := range expected {
-						assert.Equal(t, "text/plain", fileData.Greetings[i].ContentType)
-						assert.Equal(t, fmt.Sprintf("greetings_%d.txt", i+1), fileData.Greetings[i].Filename)
-						assert.Equal(t, len(e), int(fileData.Greetings[i].Size))
-						assert.True(t, fileData.Greetings[i].IsSet)
-
-						b, err := io.ReadAll(fileData.Greetings[i])
-						require.NoError(t, err)
-						assert.Equal(t, e, string(b))
-					}
-
-					return nil, nil
+					// The 1 MiB default only applies to buffered bodies.
+					n, err := io.Copy(io.Discard, input.RawBody)
+					assert.Equal(t, int64(2*1024*1024), n)
+					return nil, err
 				})
-
-				// Ensure OpenAPI spec is listed as a multipart/form-data upload with
-				// the appropriate schema.
-				mpContent := api.OpenAPI().Paths["/upload"].Post.RequestBody.Content["multipart/form-data"]
-				assert.Equal(t, "text/plain", mpContent.Encoding["file"].ContentType)
-				assert.Equal(t, "text/plain", mpContent.Encoding["greetings"].ContentType)
-				assert.Equal(t, "object", mpContent.Schema.Type)
-				assert.Equal(t, "binary", mpContent.Schema.Properties["file"].Format)
-				assert.Equal(t, "binary", mpContent.Schema.Properties["greetings"].Items.Format)
 			},
-			Method:  http.MethodPost,
-			URL:     "/upload",
-			Headers: map[string]string{"Content-Type": "multipart/form-data; boundary=SimpleBoundary"},
-			Body: `--SimpleBoundary
-Content-Disposition: form-data; name="file"; filename="test.txt"
-Content-Type: text/plain
-
-Hello, World!
---SimpleBoundary
-Content-Disposition: form-data; name="greetings"; filename="greetings_1.txt"
-Content-Type: text/plain
-
-Hello
---SimpleBoundary
-Content-Disposition: form-data; name="greetings"; filename="greetings_2.txt"
-Content-Type: text/plain
-
-World
---SimpleBoundary
-Content-Disposition: form-data; name="NoTagBinding"; filename="notag.txt"
-Content-Type: text/plain
-
-Use struct field name as fallback when no "form" tag is provided.
---SimpleBoundary--`,
+			Method: http.MethodPut,
+			URL:    "/upload",
+			Body:   strings.Repeat("a", 2*1024*1024),
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNoContent, resp.Code)
+			},
 		},
 		{
-			Name: "request-body-multipart-file-decoded-required",
+			Name: "request-body-stream-too-large",
 			Register: func(t *testing.T, api huma.API) {
 				huma.Register(api, huma.Operation{
-					Method: http.MethodPost,
-					Path:   "/upload",
+					Method:       http.MethodPut,
+					Path:         "/upload",
+					MaxBodyBytes: 5,
 				}, func(ctx context.Context, input *struct {
-					RawBody huma.MultipartFormFiles[struct {
-						HelloWorld huma.FormFile   `form:"file" contentType:"text/plain" required:"true"`
-						Sentences  []huma.FormFile `form:"greetings" contentType:"text/plain" required:"true"`
-					}]
+					RawBody io.Reader
 				}) (*struct{}, error) {
-					return nil, nil
+					_, err := io.Copy(io.Discard, input.RawBody)
+					return nil, err
 				})
-
+			},
+			Method: http.MethodPut,
+			URL:    "/upload",
+			Body:   "hello world",
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code)
+				assert.Contains(t, resp.Body.String(), "limit=5 bytes")
+			},
+		},
+		{
+			Name: "request-body-gzip",
+			Register: func(t *testing.T, api huma.API) {
+				huma.Register(api, huma.Operation{
+					Method: http.MethodPut,
+					Path:   "/body",
+				}, func(ctx context.Context, input *struct {
+					Body struct {
+						Name string `json:"name" minLength:"3"`
+					}
+				}) (*struct{}, error) {
+					assert.Equal(t, "hello", input.Body.Name)
+					return nil, nil
+				})
+			},
+			Method:  http.MethodPut,
+			URL:     "/body",
+			Headers: map[string]string{"Content-Encoding": "gzip"},
+			Body:    gzipString(`{"name": "hello"}`),
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNoContent, resp.Code)
+			},
+		},
+		{
+			Name: "request-body-gzip-too-large",
+			Register: func(t *testing.T, api huma.API) {
+				huma.Register(api, huma.Operation{
+					Method:       http.MethodPut,
+					Path:         "/body",
+					MaxBodyBytes: 100,
+				}, func(ctx context.Context, input *BodyContainer) (*struct{}, error) {
+					return nil, nil
+				})
+			},
+			Method:  http.MethodPut,
+			URL:     "/body",
+			Headers: map[string]string{"Content-Encoding": "gzip"},
+			// Compresses to well under the limit, but decompresses to far more.
+			Body: gzipString(strings.Repeat("a", 10000)),
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code)
+			},
+		},
+		{
+			Name: "request-body-gzip-invalid",
+			Register: func(t *testing.T, api huma.API) {
+				huma.Register(api, huma.Operation{
+					Method: http.MethodPut,
+					Path:   "/body",
+				}, func(ctx context.Context, input *BodyContainer) (*struct{}, error) {
+					return nil, nil
+				})
+			},
+			Method:  http.MethodPut,
+			URL:     "/body",
+			Headers: map[string]string{"Content-Encoding": "gzip"},
+			Body:    "not gzip",
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
+		{
+			Name: "request-body-gzip-truncated",
+			Register: func(t *testing.T, api huma.API) {
+				huma.Register(api, huma.Operation{
+					Method: http.MethodPut,
+					Path:   "/body",
+				}, func(ctx context.Context, input *BodyContainer) (*struct{}, error) {
+					return nil, nil
+				})
+			},
+			Method:  http.MethodPut,
+			URL:     "/body",
+			Headers: map[string]string{"Content-Encoding": "gzip"},
+			// The header is valid, but the stream is cut off partway through.
+			Body: gzipString(strings.Repeat("hello ", 100))[:20],
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+				assert.Contains(t, resp.Body.String(), "cannot decode request body")
+			},
+		},
+		{
+			Name: "request-body-gzip-raw",
+			Register: func(t *testing.T, api huma.API) {
+				huma.Register(api, huma.Operation{
+					Method: http.MethodPut,
+					Path:   "/body",
+				}, func(ctx context.Context, input *struct {
+					RawBody []byte
+				}) (*struct{}, error) {
+					// Raw bodies are passed through as sent.
+					assert.Equal(t, gzipString("hello"), string(input.RawBody))
+					return nil, nil
+				})
+			},
+			Method:  http.MethodPut,
+			URL:     "/body",
+			Headers: map[string]string{"Content-Encoding": "gzip"},
+			Body:    gzipString("hello"),
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNoContent, resp.Code)
+			},
+		},
+		{
+			Name: "request-body-gzip-raw-stream",
+			Register: func(t *testing.T, api huma.API) {
+				huma.Register(api, huma.Operation{
+					Method: http.MethodPut,
+					Path:   "/body",
+				}, func(ctx context.Context, input *struct {
+					RawBody io.Reader
+				}) (*struct{}, error) {
+					b, err := io.ReadAll(input.RawBody)
+					assert.Equal(t, gzipString("hello"), string(b))
+					return nil, err
+				})
+			},
+			Method:  http.MethodPut,
+			URL:     "/body",
+			Headers: map[string]string{"Content-Encoding": "gzip"},
+			Body:    gzipString("hello"),
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNoContent, resp.Code)
+			},
+		},
+		{
+			Name: "request-body-unsupported-encoding",
+			Register: func(t *testing.T, api huma.API) {
+				huma.Register(api, huma.Operation{
+					Method: http.MethodPut,
+					Path:   "/body",
+				}, func(ctx context.Context, input *BodyContainer) (*struct{}, error) {
+					return nil, nil
+				})
+			},
+			Method:  http.MethodPut,
+			URL:     "/body",
+			Headers: map[string]string{"Content-Encoding": "compress"},
+			Body:    "whatever",
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnsupportedMediaType, resp.Code)
+				assert.Contains(t, resp.Body.String(), "unknown content encoding: compress")
+			},
+		},
+		{
+			Name: "request-body-form",
+			Register: func(t *testing.T, api huma.API) {
+				huma.Register(api, huma.Operation{
+					Method: http.MethodPost,
+					Path:   "/form",
+				}, func(ctx context.Context, input *struct {
+					Body struct {
+						Name    string   `json:"name" form:"full_name"`
+						Age     int      `json:"age" minimum:"18"`
+						Agree   bool     `json:"agree"`
+						Tags    []string `json:"tags,omitempty"`
+						Comment string   `json:"comment,omitempty"`
+					} `contentType:"application/x-www-form-urlencoded"`
+				}) (*struct{}, error) {
+					assert.Equal(t, "Alice Smith", input.Body.Name)
+					assert.Equal(t, 30, input.Body.Age)
+					assert.True(t, input.Body.Agree)
+					assert.Equal(t, []string{"a,b", "c"}, input.Body.Tags)
+					return nil, nil
+				})
+
+				body := api.OpenAPI().Paths["/form"].Post.RequestBody
+				assert.NotNil(t, body.Content["application/x-www-form-urlencoded"].Schema)
+			},
+			Method:  http.MethodPost,
+			URL:     "/form",
+			Headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded; charset=utf-8"},
+			Body:    "full_name=Alice+Smith&age=30&agree=true&tags=a%2Cb&tags=c",
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNoContent, resp.Code)
+			},
+		},
+		{
+			Name: "request-body-form-error",
+			Register: func(t *testing.T, api huma.API) {
+				huma.Register(api, huma.Operation{
+					Method: http.MethodPost,
+					Path:   "/form",
+				}, func(ctx context.Context, input *struct {
+					Body struct {
+						Name string `json:"name"`
+						Age  int    `json:"age" minimum:"18"`
+						Size int    `json:"size,omitempty"`
+					} `contentType:"application/x-www-form-urlencoded"`
+				}) (*struct{}, error) {
+					return nil, nil
+				})
+			},
+			Method:  http.MethodPost,
+			URL:     "/form",
+			Headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+			Body:    "name=bob&age=5&size=big",
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+				assert.Contains(t, resp.Body.String(), "body.age")
+				assert.Contains(t, resp.Body.String(), "body.size")
+			},
+		},
+		{
+			Name: "request-body-file-upload",
+			Register: func(t *testing.T, api huma.API) {
+				huma.Register(api, huma.Operation{
+					Method: http.MethodPut,
+					Path:   "/file",
+				}, func(ctx context.Context, input *struct {
+					RawBody []byte `contentType:"application/foo"`
+				}) (*struct{}, error) {
+					assert.Equal(t, `some-data`, string(input.RawBody))
+					return nil, nil
+				})
+
+				// Ensure OpenAPI spec is listed as a binary upload. This enables
+				// generated documentation to show a file upload button.
+				assert.Equal(t, "binary", api.OpenAPI().Paths["/file"].Put.RequestBody.Content["application/foo"].Schema.Format)
+			},
+			Method:  http.MethodPut,
+			URL:     "/file",
+			Headers: map[string]string{"Content-Type": "application/foo"},
+			Body:    `some-data`,
+		},
+		{
+			Name: "request-body-multipart-file-decoded",
+			Register: func(t *testing.T, api huma.API) {
+				huma.Register(api, huma.Operation{
+					Method: http.MethodPost,
+					Path:   "/upload",
+				}, func(ctx context.Context, input *struct {
+					RawBody huma.MultipartFormFiles[struct {
+						HelloWorld   huma.FormFile   `form:"file" contentType:"text/plain" required:"true"`
+						Greetings    []huma.FormFile `form:"greetings" contentType:"text/plain" required:"true"`
+						NoTagBinding huma.FormFile   `contentType:"text/plain"`
+						UnusedField  string          // Ignored altogether
+					}]
+				}) (*struct{}, error) {
+					fileData := input.RawBody.Data()
+
+					assert.Equal(t, "text/plain", fileData.HelloWorld.ContentType)
+					assert.Equal(t, "test.txt", fileData.HelloWorld.Filename)
+					assert.Equal(t, len("Hello, World!"), int(fileData.HelloWorld.Size))
+					assert.True(t, fileData.HelloWorld.IsSet)
+					b, err := io.ReadAll(fileData.HelloWorld)
+					require.NoError(t, err)
+					assert.Equal(t, "Hello, World!", string(b))
+
+					assert.Equal(t, "text/plain", fileData.NoTagBinding.ContentType)
+					assert.True(t, fileData.NoTagBinding.IsSet)
+					b, err = io.ReadAll(fileData.NoTagBinding)
+					require.NoError(t, err)
+					assert.Equal(t, `Use struct field name as fallback when no "form" tag is provided.`, string(b))
+
+					expected := []string{"Hello", "World"}
+					for i, e := range expected {
+						assert.Equal(t, "text/plain", fileData.Greetings[i].ContentType)
+						assert.Equal(t, fmt.Sprintf("greetings_%d.txt", i+1), fileData.Greetings[i].Filename)
+						assert.Equal(t, len(e), int(fileData.Greetings[i].Size))
+						assert.True(t, fileData.Greetings[i].IsSet)
+
+						b, err := io.ReadAll(fileData.Greetings[i])
+						require.NoError(t, err)
+						assert.Equal(t, e, string(b))
+					}
+
+					return nil, nil
+				})
+
+				// Ensure OpenAPI spec is listed as a multipart/form-data upload with
+				// the appropriate schema.
+				mpContent := api.OpenAPI().Paths["/upload"].Post.RequestBody.Content["multipart/form-data"]
+				assert.Equal(t, "text/plain", mpContent.Encoding["file"].ContentType)
+				assert.Equal(t, "text/plain", mpContent.Encoding["greetings"].ContentType)
+				assert.Equal(t, "object", mpContent.Schema.Type)
+				assert.Equal(t, "binary", mpContent.Schema.Properties["file"].Format)
+				assert.Equal(t, "binary", mpContent.Schema.Properties["greetings"].Items.Format)
+			},
+			Method:  http.MethodPost,
+			URL:     "/upload",
+			Headers: map[string]string{"Content-Type": "multipart/form-data; boundary=SimpleBoundary"},
+			Body: `--SimpleBoundary
+Content-Disposition: form-data; name="file"; filename="test.txt"
+Content-Type: text/plain
+
+Hello, World!
+--SimpleBoundary
+Content-Disposition: form-data; name="greetings"; filename="greetings_1.txt"
+Content-Type: text/plain
+
+Hello
+--SimpleBoundary
+Content-Disposition: form-data; name="greetings"; filename="greetings_2.txt"
+Content-Type: text/plain
+
+World
+--SimpleBoundary
+Content-Disposition: form-data; name="NoTagBinding"; filename="notag.txt"
+Content-Type: text/plain
+
+Use struct field name as fallback when no "form" tag is provided.
+--SimpleBoundary--`,
+		},
+		{
+			Name: "request-body-multipart-file-decoded-required",
+			Register: func(t *testing.T, api huma.API) {
+				huma.Register(api, huma.Operation{
+					Method: http.MethodPost,
+					Path:   "/upload",
+				}, func(ctx context.Context, input *struct {
+					RawBody huma.MultipartFormFiles[struct {
+						HelloWorld huma.FormFile   `form:"file" contentType:"text/plain" required:"true"`
+						Sentences  []huma.FormFile `form:"greetings" contentType:"text/plain" required:"true"`
+					}]
+				}) (*struct{}, error) {
+					return nil, nil
+				})
+
 				// Ensure OpenAPI spec is listed as a multipart/form-data upload with
 				// the appropriate schema.
 				mpContent := api.OpenAPI().Paths["/upload"].Post.RequestBody.Content["multipart/form-data"]
@@ -874,8 +1497,8 @@ Hello, World!
 					var errors huma.ErrorModel
 					err := json.Unmarshal(resp.Body.Bytes(), &errors)
 					require.NoError(t, err)
-					assert.Equal(t, "file", errors.Errors[0].Location)
-					assert.Equal(t, "greetings", errors.Errors[1].Location)
+					assert.Equal(t, "body.file", errors.Errors[0].Location)
+					assert.Equal(t, "body.greetings", errors.Errors[1].Location)
 				}
 			},
 		},
@@ -932,7 +1555,7 @@ What are you doing here ?
 					var errors huma.ErrorModel
 					err := json.Unmarshal(resp.Body.Bytes(), &errors)
 					require.NoError(t, err)
-					assert.Equal(t, "file", errors.Errors[0].Location)
+					assert.Equal(t, "body.file", errors.Errors[0].Location)
 				}
 			},
 		},
@@ -966,7 +1589,7 @@ Hello, World!
 				err := json.Unmarshal(resp.Body.Bytes(), &errors)
 				require.NoError(t, err)
 				assert.Len(t, errors.Errors, 2) // Both single and multiple file receiver should fail
-				assert.Equal(t, "file", errors.Errors[0].Location)
+				assert.Equal(t, "body.file", errors.Errors[0].Location)
 				assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
 			},
 		},
@@ -1619,6 +2242,200 @@ Content of example2.txt.
 				assert.Equal(t, 256, resp.Code)
 			},
 		},
+		{
+			Name: "dynamic-status-enum",
+			Register: func(t *testing.T, api huma.API) {
+				type Resp struct {
+					Status int    `enum:"200,201"`
+					Loc    string `header:"Location"`
+					Body   struct {
+						ID string `json:"id"`
+					}
+				}
+
+				huma.Register(api, huma.Operation{
+					Method:       http.MethodPut,
+					Path:         "/status",
+					StrictStatus: true,
+				}, func(ctx context.Context, input *struct{}) (*Resp, error) {
+					resp := &Resp{}
+					resp.Status = http.StatusCreated
+					resp.Loc = "/status/abc"
+					resp.Body.ID = "abc"
+					return resp, nil
+				})
+
+				op := api.OpenAPI().Paths["/status"].Put
+				assert.Equal(t, http.StatusOK, op.DefaultStatus)
+				for _, code := range []string{"200", "201"} {
+					require.NotNil(t, op.Responses[code], code)
+					assert.NotNil(t, op.Responses[code].Content["application/json"].Schema, code)
+					assert.NotNil(t, op.Responses[code].Headers["Location"], code)
+				}
+				assert.Equal(t, "Created", op.Responses["201"].Description)
+				assert.NotNil(t, op.Responses["default"])
+			},
+			Method: http.MethodPut,
+			URL:    "/status",
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, resp.Code)
+				assert.Equal(t, "/status/abc", resp.Header().Get("Location"))
+			},
+		},
+		{
+			Name: "dynamic-status-strict",
+			Register: func(t *testing.T, api huma.API) {
+				type Resp struct {
+					Status int `enum:"200,201"`
+				}
+
+				huma.Register(api, huma.Operation{
+					Method:       http.MethodGet,
+					Path:         "/status",
+					StrictStatus: true,
+				}, func(ctx context.Context, input *struct{}) (*Resp, error) {
+					return &Resp{Status: http.StatusAccepted}, nil
+				})
+			},
+			Method: http.MethodGet,
+			URL:    "/status",
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, resp.Code)
+				assert.Contains(t, resp.Body.String(), "undocumented response status 202")
+			},
+		},
+		{
+			Name: "dynamic-status-strict-error",
+			Register: func(t *testing.T, api huma.API) {
+				type Resp struct {
+					Status int
+				}
+
+				huma.Register(api, huma.Operation{
+					Method:       http.MethodGet,
+					Path:         "/status",
+					Errors:       []int{http.StatusNotFound},
+					StrictStatus: true,
+				}, func(ctx context.Context, input *struct{}) (*Resp, error) {
+					return &Resp{Status: http.StatusNotFound}, nil
+				})
+
+				// The error response is documented, but not for the output.
+				assert.NotNil(t, api.OpenAPI().Paths["/status"].Get.Responses["404"])
+			},
+			Method: http.MethodGet,
+			URL:    "/status",
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, resp.Code)
+				assert.Contains(t, resp.Body.String(), "undocumented response status 404")
+			},
+		},
+		{
+			Name: "dynamic-status-strict-documented",
+			Register: func(t *testing.T, api huma.API) {
+				type Resp struct {
+					Status int
+				}
+
+				huma.Register(api, huma.Operation{
+					Method: http.MethodGet,
+					Path:   "/status",
+					Responses: map[string]*huma.Response{
+						"202": {Description: "Accepted"},
+					},
+					StrictStatus: true,
+				}, func(ctx context.Context, input *struct{}) (*Resp, error) {
+					return &Resp{Status: http.StatusAccepted}, nil
+				})
+			},
+			Method: http.MethodGet,
+			URL:    "/status",
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusAccepted, resp.Code)
+			},
+		},
+		{
+			Name: "dynamic-status-zero",
+			Register: func(t *testing.T, api huma.API) {
+				type Resp struct {
+					Status int `enum:"201,200"`
+				}
+
+				huma.Register(api, huma.Operation{
+					Method:       http.MethodGet,
+					Path:         "/status",
+					StrictStatus: true,
+				}, func(ctx context.Context, input *struct{}) (*Resp, error) {
+					return &Resp{}, nil
+				})
+			},
+			Method: http.MethodGet,
+			URL:    "/status",
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, resp.Code)
+			},
+		},
+		{
+			Name: "response-validation-fail",
+			Register: func(t *testing.T, api huma.API) {
+				type Resp struct {
+					Limit int `header:"X-Limit" maximum:"10"`
+					Body  struct {
+						Name string `json:"name" maxLength:"3"`
+					}
+				}
+
+				huma.Register(api, huma.Operation{
+					Method:            http.MethodGet,
+					Path:              "/response",
+					ValidateResponses: huma.ResponseValidationFail,
+				}, func(ctx context.Context, input *struct{}) (*Resp, error) {
+					resp := &Resp{Limit: 20}
+					resp.Body.Name = "too long"
+					return resp, nil
+				})
+			},
+			Method: http.MethodGet,
+			URL:    "/response",
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, resp.Code)
+				assert.Empty(t, resp.Header().Get("X-Limit"))
+				assert.Contains(t, resp.Body.String(), "response validation failed")
+				assert.Contains(t, resp.Body.String(), "header.X-Limit")
+				assert.Contains(t, resp.Body.String(), "body.name")
+			},
+		},
+		{
+			Name: "response-validation-callback",
+			Register: func(t *testing.T, api huma.API) {
+				type Resp struct {
+					Body struct {
+						Name string `json:"name" maxLength:"3"`
+					}
+				}
+
+				huma.Register(api, huma.Operation{
+					Method: http.MethodGet,
+					Path:   "/response",
+					ValidateResponses: func(ctx huma.Context, status int, errs []error) error {
+						assert.Equal(t, http.StatusOK, status)
+						assert.Len(t, errs, 1)
+						return nil
+					},
+				}, func(ctx context.Context, input *struct{}) (*Resp, error) {
+					resp := &Resp{}
+					resp.Body.Name = "too long"
+					return resp, nil
+				})
+			},
+			Method: http.MethodGet,
+			URL:    "/response",
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				// The callback did not return an error, so the response is sent.
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Contains(t, resp.Body.String(), "too long")
+			},
+		},
 		{
 			// Simulate a request with a body that came from another call, which
 			// includes the `$schema` field. It should be allowed to be passed
@@ -1755,38 +2572,188 @@ Content of example2.txt.
 	}
 }
 
-func TestOpenAPI(t *testing.T) {
-	r, api := humatest.New(t, huma.DefaultConfig("Features Test API", "1.0.0"))
+func TestOpenAPI(t *testing.T) {
+	r, api := humatest.New(t, huma.DefaultConfig("Features Test API", "1.0.0"))
+
+	type Resp struct {
+		Body struct {
+			Greeting string `json:"greeting"`
+		}
+	}
+
+	huma.Register(api, huma.Operation{
+		Method: http.MethodGet,
+		Path:   "/test",
+	}, func(ctx context.Context, input *struct{}) (*Resp, error) {
+		resp := &Resp{}
+		resp.Body.Greeting = "Hello, world"
+		return resp, nil
+	})
+
+	for _, url := range []string{
+		"/openapi.json",
+		"/openapi-3.0.json",
+		"/openapi.yaml",
+		"/openapi-3.0.yaml",
+		"/docs",
+		"/schemas/RespBody.json",
+	} {
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, 200, w.Code, w.Body.String())
+	}
+}
+
+func TestFormatsContent(t *testing.T) {
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.Formats = map[string]huma.Format{
+		"application/json":   huma.DefaultJSONFormat,
+		"json":               huma.DefaultJSONFormat,
+		"application/x-test": huma.DefaultJSONFormat,
+	}
+	_, api := humatest.New(t, config)
+
+	type Resp struct {
+		Body struct {
+			Greeting string `json:"greeting"`
+		}
+	}
+
+	huma.Register(api, huma.Operation{
+		Method: http.MethodPut,
+		Path:   "/test",
+	}, func(ctx context.Context, input *struct {
+		Body struct {
+			Name string `json:"name" minLength:"3"`
+		}
+	}) (*Resp, error) {
+		resp := &Resp{}
+		resp.Body.Greeting = "Hello, " + input.Body.Name
+		return resp, nil
+	})
+
+	op := api.OpenAPI().Paths["/test"].Put
+	assert.Len(t, op.RequestBody.Content, 2)
+	assert.NotNil(t, op.RequestBody.Content["application/json"].Schema)
+	assert.Same(t, op.RequestBody.Content["application/json"].Schema, op.RequestBody.Content["application/x-test"].Schema)
+	assert.Len(t, op.Responses["200"].Content, 2)
+	assert.NotNil(t, op.Responses["200"].Content["application/x-test"].Schema)
+
+	// Validation runs no matter which format the body is sent with.
+	resp := api.Put("/test", "Content-Type: application/x-test", strings.NewReader(`{"name": "a"}`))
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Contains(t, resp.Body.String(), "body.name")
+
+	resp = api.Put("/test", "Content-Type: application/x-test", strings.NewReader(`{"name": "abc"}`))
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+func TestStrictStatusConfig(t *testing.T) {
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.StrictStatus = true
+	_, api := humatest.New(t, config)
+
+	huma.Register(api, huma.Operation{
+		Method: http.MethodGet,
+		Path:   "/status/{status}",
+		Errors: []int{http.StatusNotFound},
+	}, func(ctx context.Context, input *struct {
+		Status int `path:"status"`
+	}) (*struct {
+		Status int `enum:"200,201"`
+	}, error) {
+		return &struct {
+			Status int `enum:"200,201"`
+		}{Status: input.Status}, nil
+	})
+
+	assert.Equal(t, http.StatusCreated, api.Get("/status/201").Code)
+	assert.NotNil(t, api.OpenAPI().Paths["/status/{status}"].Get.Responses["422"])
+
+	// 422 is documented because of the path param, but not for the output.
+	resp := api.Get("/status/422")
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.Contains(t, resp.Body.String(), "undocumented response status 422")
+}
+
+func TestResponseValidationConfig(t *testing.T) {
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.ValidateResponses = huma.ResponseValidationFail
+	_, api := humatest.New(t, config)
 
 	type Resp struct {
-		Body struct {
-			Greeting string `json:"greeting"`
+		Status int `enum:"200,201"`
+		Body   struct {
+			Count int `json:"count" minimum:"0"`
 		}
 	}
 
 	huma.Register(api, huma.Operation{
 		Method: http.MethodGet,
-		Path:   "/test",
-	}, func(ctx context.Context, input *struct{}) (*Resp, error) {
-		resp := &Resp{}
-		resp.Body.Greeting = "Hello, world"
+		Path:   "/count/{count}",
+	}, func(ctx context.Context, input *struct {
+		Count int `path:"count"`
+	}) (*Resp, error) {
+		resp := &Resp{Status: http.StatusOK}
+		if input.Count == 202 {
+			resp.Status = http.StatusAccepted
+		}
+		resp.Body.Count = input.Count
 		return resp, nil
 	})
 
-	for _, url := range []string{
-		"/openapi.json",
-		"/openapi-3.0.json",
-		"/openapi.yaml",
-		"/openapi-3.0.yaml",
-		"/docs",
-		"/schemas/RespBody.json",
-	} {
-		req, _ := http.NewRequest(http.MethodGet, url, nil)
-		w := httptest.NewRecorder()
-		r.ServeHTTP(w, req)
+	resp := api.Get("/count/5")
+	assert.Equal(t, http.StatusOK, resp.Code)
 
-		assert.Equal(t, 200, w.Code, w.Body.String())
+	resp = api.Get("/count/-1")
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.Contains(t, resp.Body.String(), "body.count")
+
+	resp = api.Get("/count/202")
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.Contains(t, resp.Body.String(), "undocumented response status 202")
+}
+
+type headerID struct {
+	A, B string
+}
+
+func (id *headerID) String() string {
+	return id.A + "-" + id.B
+}
+
+func TestResponseValidationHeaders(t *testing.T) {
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.ValidateResponses = huma.ResponseValidationFail
+	_, api := humatest.New(t, config)
+
+	type Resp struct {
+		Links []string `header:"Link" maxLength:"3"`
+		ID    headerID `header:"X-ID"`
 	}
+
+	huma.Get(api, "/headers", func(ctx context.Context, input *struct {
+		Link string `query:"link"`
+	}) (*Resp, error) {
+		resp := &Resp{Links: []string{"a", "b"}, ID: headerID{A: "a", B: "b"}}
+		if input.Link != "" {
+			resp.Links = append(resp.Links, input.Link)
+		}
+		return resp, nil
+	})
+
+	// Slice headers are validated one value at a time and `fmt.Stringer`
+	// headers are validated as the string which is sent.
+	resp := api.Get("/headers")
+	assert.Equal(t, http.StatusNoContent, resp.Code, resp.Body.String())
+	assert.Equal(t, []string{"a", "b"}, resp.Header().Values("Link"))
+	assert.Equal(t, "a-b", resp.Header().Get("X-ID"))
+
+	resp = api.Get("/headers?link=toolong")
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.Contains(t, resp.Body.String(), "header.Link[2]")
 }
 
 type IntNot3 int
@@ -1940,6 +2907,73 @@ func TestCustomError(t *testing.T) {
 	assert.Equal(t, `{"$schema":"http://localhost/schemas/MyError.json","message":"not found","details":["some-other-error"]}`+"\n", resp.Body.String())
 }
 
+type ConflictError struct {
+	status        int
+	Detail        string `json:"detail"`
+	ConflictingID string `json:"conflictingId,omitempty"`
+}
+
+func (e *ConflictError) Error() string {
+	return e.Detail
+}
+
+func (e *ConflictError) GetStatus() int {
+	return e.status
+}
+
+func TestErrorModels(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	huma.Register(api, huma.Operation{
+		OperationID: "put-thing",
+		Method:      http.MethodPut,
+		Path:        "/things/{id}",
+		Errors:      []int{http.StatusNotFound},
+		ErrorModels: map[int]huma.ErrorFactory{
+			http.StatusConflict: func(status int, msg string, errs ...error) huma.StatusError {
+				return &ConflictError{status: status, Detail: msg}
+			},
+			http.StatusUnprocessableEntity: func(status int, msg string, errs ...error) huma.StatusError {
+				return &MyError{status: status, Message: msg, Details: []string{"custom"}}
+			},
+		},
+	}, func(ctx context.Context, input *struct {
+		ID int `path:"id" maximum:"10"`
+	}) (*struct{}, error) {
+		if input.ID == 2 {
+			// The generic error helpers use the registered model too.
+			return nil, huma.Error409Conflict("thing is locked")
+		}
+		return nil, &ConflictError{
+			status:        http.StatusConflict,
+			Detail:        "thing already exists",
+			ConflictingID: "abc123",
+		}
+	})
+
+	op := api.OpenAPI().Paths["/things/{id}"].Put
+	registry := api.OpenAPI().Components.Schemas
+	assert.Contains(t, registry.SchemaFromRef(op.Responses["409"].Content["application/json"].Schema.Ref).Properties, "conflictingId")
+	assert.Contains(t, registry.SchemaFromRef(op.Responses["422"].Content["application/json"].Schema.Ref).Properties, "details")
+	assert.Contains(t, op.Responses["404"].Content, "application/problem+json")
+	assert.NotNil(t, op.Responses["500"])
+
+	resp := api.Put("/things/1", map[string]any{})
+	assert.Equal(t, http.StatusConflict, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"conflictingId":"abc123"`)
+
+	resp = api.Put("/things/2", map[string]any{})
+	assert.Equal(t, http.StatusConflict, resp.Code)
+	assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+	assert.Contains(t, resp.Body.String(), "ConflictError.json")
+	assert.Contains(t, resp.Body.String(), `"detail":"thing is locked"`)
+
+	// Errors written by Huma itself use the registered model too.
+	resp = api.Put("/things/20", map[string]any{})
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"details":["custom"]`)
+}
+
 type NestedResolversStruct struct {
 	Field2 string `json:"field2"`
 }
@@ -2034,9 +3068,78 @@ func TestResolverCompositionCalledOnce(t *testing.T) {
 	assert.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
 }
 
-func TestParamPointerPanics(t *testing.T) {
-	// For now, we don't support these, so we panic rather than have subtle
-	// bugs that are hard to track down.
+func TestParamsRepeatedHeaders(t *testing.T) {
+	r, app := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+	huma.Register(app, huma.Operation{
+		OperationID: "test",
+		Method:      http.MethodGet,
+		Path:        "/test",
+	}, func(ctx context.Context, input *struct {
+		Tags   []string `header:"X-Tag"`
+		Single string   `header:"X-Single"`
+	}) (*struct{}, error) {
+		assert.Equal(t, []string{"a", "b", "c"}, input.Tags)
+		assert.Equal(t, "one", input.Single)
+		return nil, nil
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Add("X-Tag", "a,b")
+	req.Header.Add("X-Tag", "c")
+	req.Header.Add("X-Single", "one")
+	req.Header.Add("X-Single", "two")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+}
+
+func TestParamExplodeNonSlicePanics(t *testing.T) {
+	_, app := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	assert.Panics(t, func() {
+		huma.Register(app, huma.Operation{
+			OperationID: "bug",
+			Method:      http.MethodGet,
+			Path:        "/bug",
+		}, func(ctx context.Context, input *struct {
+			Param string `query:"param" explode:"true"`
+		}) (*struct{}, error) {
+			return nil, nil
+		})
+	})
+}
+
+func TestParamDeepObjectDefaultPanics(t *testing.T) {
+	_, app := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	assert.PanicsWithError(t, "default is not supported for deepObject query param filter", func() {
+		huma.Register(app, huma.Operation{
+			OperationID: "bug",
+			Method:      http.MethodGet,
+			Path:        "/bug",
+		}, func(ctx context.Context, input *struct {
+			Filter map[string]any `query:"filter" default:"{}"`
+		}) (*struct{}, error) {
+			return nil, nil
+		})
+	})
+
+	assert.PanicsWithError(t, "default is not supported for deepObject query param filter", func() {
+		huma.Register(app, huma.Operation{
+			OperationID: "bug2",
+			Method:      http.MethodGet,
+			Path:        "/bug2",
+		}, func(ctx context.Context, input *struct {
+			Filter *struct {
+				Status string `json:"status"`
+			} `query:"filter" default:"{}"`
+		}) (*struct{}, error) {
+			return nil, nil
+		})
+	})
+}
+
+func TestStatusEnumInvalidPanics(t *testing.T) {
 	_, app := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
 
 	assert.Panics(t, func() {
@@ -2044,8 +3147,408 @@ func TestParamPointerPanics(t *testing.T) {
 			OperationID: "bug",
 			Method:      http.MethodGet,
 			Path:        "/bug",
+		}, func(ctx context.Context, input *struct{}) (*struct {
+			Status int `enum:"200,created"`
+		}, error) {
+			return nil, nil
+		})
+	})
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+type timeoutReader struct{}
+
+func (timeoutReader) Read(p []byte) (int, error) {
+	return 0, timeoutError{}
+}
+
+func TestRawBodyStreamTimeout(t *testing.T) {
+	r, app := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+	huma.Register(app, huma.Operation{
+		OperationID: "upload",
+		Method:      http.MethodPut,
+		Path:        "/upload",
+	}, func(ctx context.Context, input *struct {
+		RawBody io.Reader
+	}) (*struct{}, error) {
+		_, err := io.Copy(io.Discard, input.RawBody)
+		return nil, err
+	})
+
+	req, _ := http.NewRequest(http.MethodPut, "/upload", timeoutReader{})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusRequestTimeout, w.Code, w.Body.String())
+}
+
+type humaContext huma.Context
+
+// deadlineContext records the read deadlines set for a request.
+type deadlineContext struct {
+	humaContext
+	deadlines []time.Time
+}
+
+func (c *deadlineContext) SetReadDeadline(t time.Time) error {
+	c.deadlines = append(c.deadlines, t)
+	return nil
+}
+
+func TestRawBodyStreamIdleTimeout(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+	var dc *deadlineContext
+	api.UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+		dc = &deadlineContext{humaContext: ctx}
+		next(dc)
+	})
+	huma.Register(api, huma.Operation{
+		OperationID:     "upload",
+		Method:          http.MethodPut,
+		Path:            "/upload",
+		BodyReadTimeout: time.Second,
+	}, func(ctx context.Context, input *struct {
+		RawBody io.Reader
+	}) (*struct{}, error) {
+		buf := make([]byte, 4)
+		for {
+			if _, err := input.RawBody.Read(buf); err != nil {
+				return nil, nil
+			}
+		}
+	})
+
+	resp := api.Put("/upload", strings.NewReader("a long upload"))
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+
+	// The deadline is extended on every read rather than being set once.
+	assert.Greater(t, len(dc.deadlines), 3)
+}
+
+func TestRawBodyStreamWithBodyPanics(t *testing.T) {
+	_, app := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	assert.Panics(t, func() {
+		huma.Register(app, huma.Operation{
+			OperationID: "bug",
+			Method:      http.MethodPut,
+			Path:        "/bug",
+		}, func(ctx context.Context, input *struct {
+			RawBody io.Reader
+			Body    struct{}
+		}) (*struct{}, error) {
+			return nil, nil
+		})
+	})
+}
+
+type multipartTestFile struct {
+	field, name, contentType, data string
+}
+
+func multipartBody(t *testing.T, files ...multipartTestFile) (string, *bytes.Buffer) {
+	buf := &bytes.Buffer{}
+	w := multipart.NewWriter(buf)
+	for _, f := range files {
+		h := make(map[string][]string)
+		h["Content-Disposition"] = []string{fmt.Sprintf(`form-data; name="%s"; filename="%s"`, f.field, f.name)}
+		if f.contentType != "" {
+			h["Content-Type"] = []string{f.contentType}
+		}
+		part, err := w.CreatePart(h)
+		require.NoError(t, err)
+		_, err = part.Write([]byte(f.data))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return "Content-Type: " + w.FormDataContentType(), buf
+}
+
+type uploadStreamForm struct {
+	Doc    huma.FormFile   `form:"doc" contentType:"text/plain" maxLength:"10" required:"true"`
+	Photos []huma.FormFile `form:"photos" contentType:"image/png" maxItems:"2"`
+}
+
+func TestMultipartFormStream(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	huma.Register(api, huma.Operation{
+		OperationID: "upload",
+		Method:      http.MethodPost,
+		Path:        "/upload",
+	}, func(ctx context.Context, input *struct {
+		RawBody huma.MultipartFormStream[uploadStreamForm]
+	}) (*struct{ Body []string }, error) {
+		received := []string{}
+		for {
+			part, err := input.RawBody.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			b, err := io.ReadAll(part)
+			if err != nil {
+				return nil, err
+			}
+			received = append(received, fmt.Sprintf("%s[%d] %s %s", part.Name, part.Index, part.ContentType, b))
+		}
+		return &struct{ Body []string }{Body: received}, nil
+	})
+
+	mp := api.OpenAPI().Paths["/upload"].Post.RequestBody.Content["multipart/form-data"]
+	require.NotNil(t, mp)
+	assert.Equal(t, "text/plain", mp.Encoding["doc"].ContentType)
+	assert.Equal(t, "image/png", mp.Encoding["photos"].ContentType)
+	assert.Equal(t, 10, *mp.Schema.Properties["doc"].MaxLength)
+	assert.Equal(t, 2, *mp.Schema.Properties["photos"].MaxItems)
+	assert.Equal(t, []string{"doc"}, mp.Schema.Required)
+
+	png := "\x89PNG\r\n\x1a\n"
+
+	for _, item := range []struct {
+		name   string
+		files  []multipartTestFile
+		status int
+		body   string
+	}{
+		{
+			name: "success",
+			files: []multipartTestFile{
+				{"doc", "doc.txt", "text/plain", "hello"},
+				{"photos", "a.png", "", png},
+				{"photos", "b.png", "image/png", png},
+			},
+			status: http.StatusOK,
+			body:   "photos[1] image/png",
+		},
+		{
+			name:   "too-large",
+			files:  []multipartTestFile{{"doc", "doc.txt", "text/plain", "hello, world!"}},
+			status: http.StatusRequestEntityTooLarge,
+			body:   "File too large",
+		},
+		{
+			name: "too-many",
+			files: []multipartTestFile{
+				{"doc", "doc.txt", "text/plain", "hello"},
+				{"photos", "a.png", "image/png", png},
+				{"photos", "b.png", "image/png", png},
+				{"photos", "c.png", "image/png", png},
+			},
+			status: http.StatusUnprocessableEntity,
+			body:   "Too many files",
+		},
+		{
+			name:   "bad-type",
+			files:  []multipartTestFile{{"photos", "a.png", "image/jpeg", "abc"}},
+			status: http.StatusUnprocessableEntity,
+			body:   `"location":"body.photos[0]"`,
+		},
+		{
+			name:   "unexpected-field",
+			files:  []multipartTestFile{{"other", "a.txt", "text/plain", "abc"}},
+			status: http.StatusUnprocessableEntity,
+			body:   "Unexpected form field",
+		},
+		{
+			name:   "missing-required",
+			files:  []multipartTestFile{{"photos", "a.png", "image/png", png}},
+			status: http.StatusUnprocessableEntity,
+			body:   `"message":"Field required","location":"body.doc"`,
+		},
+	} {
+		t.Run(item.name, func(t *testing.T) {
+			ct, body := multipartBody(t, item.files...)
+			resp := api.Post("/upload", ct, body)
+			assert.Equal(t, item.status, resp.Code, resp.Body.String())
+			assert.Contains(t, resp.Body.String(), item.body)
+		})
+	}
+}
+
+func TestMultipartFormStreamGzipTruncated(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	huma.Post(api, "/upload", func(ctx context.Context, input *struct {
+		RawBody huma.MultipartFormStream[uploadStreamForm]
+	}) (*struct{}, error) {
+		for {
+			part, err := input.RawBody.NextPart()
+			if err == io.EOF {
+				return nil, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			if _, err := io.ReadAll(part); err != nil {
+				return nil, err
+			}
+		}
+	})
+
+	// The stream is cut off partway through, so it can't be decoded.
+	ct, body := multipartBody(t, multipartTestFile{"doc", "doc.txt", "text/plain", "hello"})
+	resp := api.Post("/upload", ct, "Content-Encoding: gzip", strings.NewReader(gzipString(body.String())[:20]))
+	assert.Equal(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+}
+
+func TestMultipartFormFilesLimits(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	huma.Register(api, huma.Operation{
+		OperationID: "upload",
+		Method:      http.MethodPost,
+		Path:        "/upload",
+	}, func(ctx context.Context, input *struct {
+		RawBody huma.MultipartFormFiles[uploadStreamForm]
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	mp := api.OpenAPI().Paths["/upload"].Post.RequestBody.Content["multipart/form-data"]
+	assert.Equal(t, 10, *mp.Schema.Properties["doc"].MaxLength)
+	assert.Equal(t, 2, *mp.Schema.Properties["photos"].MaxItems)
+
+	ct, body := multipartBody(t, multipartTestFile{"doc", "doc.txt", "text/plain", "hello, world!"})
+	resp := api.Post("/upload", ct, body)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Contains(t, resp.Body.String(), "File too large")
+
+	png := "\x89PNG\r\n\x1a\n"
+	ct, body = multipartBody(t,
+		multipartTestFile{"doc", "doc.txt", "text/plain", "hello"},
+		multipartTestFile{"photos", "a.png", "image/png", png},
+		multipartTestFile{"photos", "b.png", "image/png", png},
+		multipartTestFile{"photos", "c.png", "image/png", png},
+	)
+	resp = api.Post("/upload", ct, body)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Contains(t, resp.Body.String(), "Too many files")
+}
+
+type uploadMeta struct {
+	Author string `json:"author" minLength:"2"`
+}
+
+func TestMultipartFormFilesFields(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	type Form struct {
+		Doc   huma.FormFile `form:"doc" contentType:"text/plain"`
+		Title string        `form:"title" minLength:"3" required:"true"`
+		Count int           `form:"count" maximum:"10"`
+		Tags  []string      `form:"tags"`
+		Meta  uploadMeta    `form:"meta"`
+	}
+
+	huma.Register(api, huma.Operation{
+		OperationID: "upload",
+		Method:      http.MethodPost,
+		Path:        "/upload",
+	}, func(ctx context.Context, input *struct {
+		RawBody huma.MultipartFormFiles[Form]
+	}) (*struct{}, error) {
+		data := input.RawBody.Data()
+		assert.True(t, data.Doc.IsSet)
+		assert.Equal(t, "Hello", data.Title)
+		assert.Equal(t, 5, data.Count)
+		assert.Equal(t, []string{"a", "b"}, data.Tags)
+		assert.Equal(t, "Jane", data.Meta.Author)
+		return nil, nil
+	})
+
+	mp := api.OpenAPI().Paths["/upload"].Post.RequestBody.Content["multipart/form-data"]
+	assert.Equal(t, "string", mp.Schema.Properties["title"].Type)
+	assert.Equal(t, 3, *mp.Schema.Properties["title"].MinLength)
+	assert.Equal(t, "integer", mp.Schema.Properties["count"].Type)
+	assert.Equal(t, "array", mp.Schema.Properties["tags"].Type)
+	assert.Equal(t, "#/components/schemas/UploadMeta", mp.Schema.Properties["meta"].Ref)
+	assert.Equal(t, []string{"title"}, mp.Schema.Required)
+	assert.Equal(t, "text/plain", mp.Encoding["title"].ContentType)
+	assert.Equal(t, "application/json", mp.Encoding["meta"].ContentType)
+
+	send := func(fields map[string][]string) *httptest.ResponseRecorder {
+		buf := &bytes.Buffer{}
+		w := multipart.NewWriter(buf)
+		part, err := w.CreateFormFile("doc", "doc.txt")
+		require.NoError(t, err)
+		_, err = part.Write([]byte("hello"))
+		require.NoError(t, err)
+		for name, values := range fields {
+			for _, value := range values {
+				require.NoError(t, w.WriteField(name, value))
+			}
+		}
+		require.NoError(t, w.Close())
+		return api.Post("/upload", "Content-Type: "+w.FormDataContentType(), buf)
+	}
+
+	resp := send(map[string][]string{
+		"title": {"Hello"},
+		"count": {"5"},
+		"tags":  {"a", "b"},
+		"meta":  {`{"author": "Jane"}`},
+	})
+	assert.Equal(t, http.StatusNoContent, resp.Code, resp.Body.String())
+
+	resp = send(map[string][]string{
+		"title": {"Hi"},
+		"count": {"50"},
+		"meta":  {`{"author": "J"}`},
+	})
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code, resp.Body.String())
+	var model huma.ErrorModel
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &model))
+	locations := []string{}
+	for _, e := range model.Errors {
+		locations = append(locations, e.Location)
+	}
+	assert.ElementsMatch(t, []string{"body.title", "body.count", "body.meta.author"}, locations)
+
+	resp = send(map[string][]string{
+		"count": {"abc"},
+		"meta":  {`{bad`},
+	})
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code, resp.Body.String())
+	assert.Contains(t, resp.Body.String(), `"location":"body.title"`)
+	assert.Contains(t, resp.Body.String(), `"location":"body.count"`)
+	assert.Contains(t, resp.Body.String(), "invalid JSON")
+
+	// Decoding directly also validates the fields.
+	files := huma.MultipartFormFiles[Form]{Form: &multipart.Form{
+		Value: map[string][]string{
+			"title": {"Hi"},
+			"count": {"5"},
+			"meta":  {`{"author": "J"}`},
+		},
+		File: map[string][]*multipart.FileHeader{},
+	}}
+	errs := files.Decode(mp)
+	locations = []string{}
+	for _, e := range errs {
+		locations = append(locations, e.(*huma.ErrorDetail).Location)
+	}
+	assert.ElementsMatch(t, []string{"body.title", "body.meta.author"}, locations)
+	assert.Equal(t, 5, files.Data().Count)
+}
+
+func TestMultipartFormStreamWithBodyPanics(t *testing.T) {
+	_, app := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	assert.Panics(t, func() {
+		huma.Register(app, huma.Operation{
+			OperationID: "bug",
+			Method:      http.MethodPut,
+			Path:        "/bug",
 		}, func(ctx context.Context, input *struct {
-			Param *string `query:"param"`
+			RawBody huma.MultipartFormStream[uploadStreamForm]
+			Body    struct{}
 		}) (*struct{}, error) {
 			return nil, nil
 		})
@@ -0,0 +1,376 @@
+// Package idempotency makes unsafe operations like `POST` and `PATCH` safe to
+// retry using the `Idempotency-Key` request header. The first response for a
+// key is stored and replayed for any duplicate requests, so e.g. a payment is
+// only made once even if the client retries after a network error.
+//
+//	api := humachi.New(router, huma.DefaultConfig("My API", "1.0.0"))
+//	idempotency.Enable(api, idempotency.Config{})
+//
+//	// Register operations *after* enabling idempotency.
+//	huma.Post(api, "/payments", createPayment, idempotency.WithIdempotency())
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// MetadataKey is the operation metadata key used to opt an operation in.
+const MetadataKey = "idempotency"
+
+// HeaderName is the request header containing the idempotency key.
+const HeaderName = "Idempotency-Key"
+
+// maxKeyLength is the maximum length of an idempotency key.
+const maxKeyLength = 255
+
+// Record is a stored response for an idempotency key.
+type Record struct {
+	// Fingerprint of the request which created the record.
+	Fingerprint string
+
+	// Done is false while the first request is still being processed.
+	Done bool
+
+	// Status, Headers and Body of the stored response.
+	Status  int
+	Headers http.Header
+	Body    []byte
+
+	// Expires is when the record may be removed from the store.
+	Expires time.Time
+}
+
+// Store keeps track of the requests and responses for idempotency keys.
+// Implement this to share keys between several instances of a service, e.g.
+// using Redis or a database. All methods must be safe for concurrent use.
+type Store interface {
+	// Begin atomically claims the key for a new request. If the key is already
+	// claimed, then the existing record is returned instead, which is not yet
+	// done if the request is in progress.
+	Begin(ctx context.Context, key string, record *Record) (existing *Record, err error)
+
+	// Complete stores the finished response for a claimed key.
+	Complete(ctx context.Context, key string, record *Record) error
+
+	// Abort releases a claimed key without storing a response, so the request
+	// can be retried.
+	Abort(ctx context.Context, key string) error
+}
+
+// Config describes how idempotency keys are handled.
+type Config struct {
+	// Store keeps track of keys. Defaults to a new in-memory store.
+	Store Store
+
+	// TTL is how long responses are kept. Defaults to 24 hours.
+	TTL time.Duration
+
+	// Required makes the `Idempotency-Key` header required for opted-in
+	// operations. If false, requests without the header are not deduplicated.
+	Required bool
+
+	// Key identifies the client making the request, e.g. the authenticated
+	// principal, so that keys from different clients never collide and
+	// clients never get each other's responses. Defaults to a hash of the
+	// request's credentials: the `Authorization` header and any `apiKey`
+	// security scheme headers, query params, and cookies documented in the
+	// OpenAPI. Set this if clients are identified in any other way, otherwise
+	// all such clients share one set of keys.
+	Key func(ctx huma.Context) string
+}
+
+// WithIdempotency opts an operation into idempotency key handling. It can be
+// passed to the convenience methods like `huma.Post` or used as a group
+// modifier.
+func WithIdempotency() func(op *huma.Operation) {
+	return func(op *huma.Operation) {
+		if op.Metadata == nil {
+			op.Metadata = map[string]any{}
+		}
+		op.Metadata[MetadataKey] = true
+	}
+}
+
+func enabled(op *huma.Operation) bool {
+	if op == nil || op.Metadata == nil {
+		return false
+	}
+	b, _ := op.Metadata[MetadataKey].(bool)
+	return b
+}
+
+// Enable idempotency key handling for opted-in operations of the API. It
+// panics if operations have already been registered. Opted-in operations
+// document the `Idempotency-Key` header and the possible errors in the
+// OpenAPI.
+//
+// Responses with a 5xx status are not stored, so the request can be retried.
+func Enable(api huma.API, config Config) {
+	huma.RequireNoOperations(api, "idempotency.Enable")
+
+	c := &config
+	if c.Store == nil {
+		c.Store = NewMemoryStore()
+	}
+	if c.TTL == 0 {
+		c.TTL = 24 * time.Hour
+	}
+
+	oapi := api.OpenAPI()
+	oapi.OnAddOperation = append(oapi.OnAddOperation, func(oapi *huma.OpenAPI, op *huma.Operation) {
+		if enabled(op) {
+			c.document(oapi, op)
+		}
+	})
+
+	api.UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+		op := ctx.Operation()
+		if !enabled(op) {
+			next(ctx)
+			return
+		}
+
+		idempotencyKey := ctx.Header(HeaderName)
+		if idempotencyKey == "" {
+			if c.Required {
+				huma.WriteErr(api, ctx, http.StatusBadRequest, HeaderName+" header is required")
+				return
+			}
+			next(ctx)
+			return
+		}
+		if len(idempotencyKey) > maxKeyLength {
+			huma.WriteErr(api, ctx, http.StatusBadRequest, HeaderName+" header is too long")
+			return
+		}
+
+		// Keys are scoped to the client and to the full request path, which
+		// includes any version or group prefix, so several APIs can share a
+		// store.
+		client := ""
+		if c.Key != nil {
+			client = c.Key(ctx)
+		} else {
+			client = credentials(oapi, ctx)
+		}
+		key := client + ":" + ctx.Method() + " " + ctx.URL().Path + ":" + idempotencyKey
+
+		// Buffer the body to fingerprint the request, then hand it on to the
+		// operation unchanged.
+		body := ctx.BodyReader()
+		var buf []byte
+		if body != nil {
+			// Apply the operation's read timeout, as the body is read here
+			// before the operation gets to it.
+			if op.BodyReadTimeout > 0 {
+				ctx.SetReadDeadline(time.Now().Add(op.BodyReadTimeout))
+			} else if op.BodyReadTimeout < 0 {
+				ctx.SetReadDeadline(time.Time{})
+			}
+			limited := io.Reader(body)
+			if op.MaxBodyBytes > 0 {
+				limited = io.LimitReader(body, op.MaxBodyBytes+1)
+			}
+			var err error
+			if buf, err = io.ReadAll(limited); err != nil {
+				if e, ok := err.(net.Error); ok && e.Timeout() {
+					huma.WriteErr(api, ctx, http.StatusRequestTimeout, "request body read timeout")
+					return
+				}
+				huma.WriteErr(api, ctx, http.StatusBadRequest, "unable to read request body", err)
+				return
+			}
+		}
+		fp := fingerprint(ctx, buf)
+
+		existing, err := c.Store.Begin(ctx.Context(), key, &Record{
+			Fingerprint: fp,
+			Expires:     time.Now().Add(c.TTL),
+		})
+		if err != nil {
+			huma.WriteErr(api, ctx, http.StatusInternalServerError, "unable to check idempotency key", err)
+			return
+		}
+		if existing != nil {
+			switch {
+			case existing.Fingerprint != fp:
+				huma.WriteErr(api, ctx, http.StatusUnprocessableEntity, HeaderName+" was already used for a different request")
+			case !existing.Done:
+				huma.WriteErr(api, ctx, http.StatusConflict, "a request with this "+HeaderName+" is in progress")
+			default:
+				replay(ctx, existing)
+			}
+			return
+		}
+
+		rec := &recorder{humaContext: ctx, headers: http.Header{}}
+		if body != nil {
+			rec.body = io.MultiReader(bytes.NewReader(buf), body)
+		}
+		completed := false
+		defer func() {
+			// Release the key if the handler panics.
+			if !completed {
+				c.Store.Abort(ctx.Context(), key)
+			}
+		}()
+		next(rec)
+		completed = true
+
+		status := rec.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		if status >= http.StatusInternalServerError {
+			c.Store.Abort(ctx.Context(), key)
+			return
+		}
+		c.Store.Complete(ctx.Context(), key, &Record{
+			Fingerprint: fp,
+			Done:        true,
+			Status:      status,
+			Headers:     rec.headers,
+			Body:        rec.buf.Bytes(),
+			Expires:     time.Now().Add(c.TTL),
+		})
+	})
+}
+
+// fingerprint identifies a request by its method, URL and body.
+func fingerprint(ctx huma.Context, body []byte) string {
+	u := ctx.URL()
+	h := sha256.New()
+	h.Write([]byte(ctx.Method() + " " + u.Path + "?" + u.RawQuery + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// credentials returns a hash of the credentials sent with the request for
+// the API's security schemes.
+func credentials(oapi *huma.OpenAPI, ctx huma.Context) string {
+	h := sha256.New()
+	h.Write([]byte(ctx.Header("Authorization")))
+	if oapi.Components != nil {
+		names := make([]string, 0, len(oapi.Components.SecuritySchemes))
+		for name := range oapi.Components.SecuritySchemes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			scheme := oapi.Components.SecuritySchemes[name]
+			if scheme == nil || scheme.Type != "apiKey" {
+				continue
+			}
+			value := ""
+			switch scheme.In {
+			case "header":
+				value = ctx.Header(scheme.Name)
+			case "query":
+				value = ctx.Query(scheme.Name)
+			case "cookie":
+				if cookie, err := huma.ReadCookie(ctx, scheme.Name); err == nil {
+					value = cookie.Value
+				}
+			}
+			h.Write([]byte("\n" + value))
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// replay writes a stored response. Headers replace any which were already
+// set, e.g. by middleware, just like they did for the original response.
+func replay(ctx huma.Context, record *Record) {
+	for name, values := range record.Headers {
+		for i, v := range values {
+			if i == 0 {
+				ctx.SetHeader(name, v)
+			} else {
+				ctx.AppendHeader(name, v)
+			}
+		}
+	}
+	ctx.SetHeader("Idempotent-Replayed", "true")
+	ctx.SetStatus(record.Status)
+	if len(record.Body) > 0 {
+		ctx.BodyWriter().Write(record.Body)
+	}
+}
+
+type (
+	humaContext huma.Context
+
+	// recorder wraps a `huma.Context` to record the response while writing
+	// it, and to read the request body from the buffered copy.
+	recorder struct {
+		humaContext
+		body    io.Reader
+		headers http.Header
+		buf     bytes.Buffer
+	}
+)
+
+func (r *recorder) BodyReader() io.Reader {
+	if r.body != nil {
+		return r.body
+	}
+	return r.humaContext.BodyReader()
+}
+
+func (r *recorder) SetHeader(name, value string) {
+	r.headers.Set(name, value)
+	r.humaContext.SetHeader(name, value)
+}
+
+func (r *recorder) AppendHeader(name, value string) {
+	r.headers.Add(name, value)
+	r.humaContext.AppendHeader(name, value)
+}
+
+func (r *recorder) BodyWriter() io.Writer {
+	return io.MultiWriter(r.humaContext.BodyWriter(), &r.buf)
+}
+
+// document adds the `Idempotency-Key` header and error responses to the
+// operation.
+func (c *Config) document(oapi *huma.OpenAPI, op *huma.Operation) {
+	found := false
+	for _, p := range op.Parameters {
+		if p.In == "header" && http.CanonicalHeaderKey(p.Name) == HeaderName {
+			found = true
+			break
+		}
+	}
+	if !found {
+		maxLength := maxKeyLength
+		op.Parameters = append(op.Parameters, &huma.Param{
+			Name:        HeaderName,
+			In:          "header",
+			Description: "Unique key which makes the request safe to retry. Duplicate requests with the same key get the original response.",
+			Required:    c.Required,
+			Schema:      &huma.Schema{Type: "string", MaxLength: &maxLength},
+		})
+	}
+
+	if op.Responses == nil {
+		op.Responses = map[string]*huma.Response{}
+	}
+	for _, status := range []int{http.StatusBadRequest, http.StatusConflict, http.StatusUnprocessableEntity} {
+		code := strconv.Itoa(status)
+		if op.Responses[code] != nil {
+			continue
+		}
+		op.Responses[code] = huma.ErrorResponse(oapi.Components.Schemas, op, status)
+	}
+}
@@ -0,0 +1,318 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+)
+
+type PaymentInput struct {
+	Body struct {
+		Amount int `json:"amount"`
+	}
+}
+
+type PaymentOutput struct {
+	Location string `header:"Location"`
+	Body     struct {
+		ID     int `json:"id"`
+		Amount int `json:"amount"`
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	existing, err := s.Begin(ctx, "a", &Record{Fingerprint: "1", Expires: now.Add(time.Hour)})
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+
+	existing, _ = s.Begin(ctx, "a", &Record{Fingerprint: "2", Expires: now.Add(time.Hour)})
+	require.NotNil(t, existing)
+	assert.Equal(t, "1", existing.Fingerprint)
+	assert.False(t, existing.Done)
+
+	s.Complete(ctx, "a", &Record{Fingerprint: "1", Done: true, Status: 201, Expires: now.Add(time.Hour)})
+	existing, _ = s.Begin(ctx, "a", &Record{Fingerprint: "1", Expires: now.Add(time.Hour)})
+	require.NotNil(t, existing)
+	assert.True(t, existing.Done)
+
+	// Aborted keys can be claimed again.
+	s.Begin(ctx, "b", &Record{Expires: now.Add(time.Hour)})
+	s.Abort(ctx, "b")
+	existing, _ = s.Begin(ctx, "b", &Record{Expires: now.Add(time.Hour)})
+	assert.Nil(t, existing)
+
+	// Expired records are replaced, and eventually removed.
+	now = now.Add(2 * time.Hour)
+	existing, _ = s.Begin(ctx, "a", &Record{Fingerprint: "3", Expires: now.Add(time.Hour)})
+	assert.Nil(t, existing)
+	assert.Len(t, s.records, 1)
+}
+
+func TestIdempotency(t *testing.T) {
+	_, api := humatest.New(t)
+	Enable(api, Config{
+		Key: func(ctx huma.Context) string {
+			return ctx.Header("X-User")
+		},
+	})
+
+	calls := 0
+	huma.Post(api, "/payments", func(ctx context.Context, input *PaymentInput) (*PaymentOutput, error) {
+		calls++
+		if input.Body.Amount < 0 {
+			return nil, errors.New("boom")
+		}
+		resp := &PaymentOutput{Location: "/payments/1"}
+		resp.Body.ID = calls
+		resp.Body.Amount = input.Body.Amount
+		return resp, nil
+	}, WithIdempotency())
+
+	body := map[string]any{"amount": 100}
+
+	resp := api.Post("/payments", "Idempotency-Key: abc", body)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "/payments/1", resp.Header().Get("Location"))
+	assert.Empty(t, resp.Header().Get("Idempotent-Replayed"))
+	first := resp.Body.String()
+
+	// Duplicates get the stored response.
+	resp = api.Post("/payments", "Idempotency-Key: abc", body)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "/payments/1", resp.Header().Get("Location"))
+	assert.Equal(t, "true", resp.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, first, resp.Body.String())
+	assert.Equal(t, 1, calls)
+
+	// A different payload with the same key is rejected.
+	resp = api.Post("/payments", "Idempotency-Key: abc", map[string]any{"amount": 200})
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Equal(t, 1, calls)
+
+	// Keys are scoped to the client.
+	assert.Equal(t, http.StatusOK, api.Post("/payments", "Idempotency-Key: abc", "X-User: bob", body).Code)
+	assert.Equal(t, 2, calls)
+
+	// Requests without a key are not deduplicated.
+	api.Post("/payments", body)
+	api.Post("/payments", body)
+	assert.Equal(t, 4, calls)
+
+	// Server errors are not stored, so the request can be retried.
+	assert.Equal(t, http.StatusInternalServerError, api.Post("/payments", "Idempotency-Key: fail", map[string]any{"amount": -1}).Code)
+	assert.Equal(t, http.StatusInternalServerError, api.Post("/payments", "Idempotency-Key: fail", map[string]any{"amount": -1}).Code)
+	assert.Equal(t, 6, calls)
+}
+
+func TestInProgress(t *testing.T) {
+	_, api := humatest.New(t)
+	Enable(api, Config{Required: true})
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	huma.Patch(api, "/things/{id}", func(ctx context.Context, input *PaymentInput) (*struct{}, error) {
+		close(started)
+		<-release
+		return nil, nil
+	}, WithIdempotency())
+
+	body := map[string]any{"amount": 1}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		assert.Equal(t, http.StatusNoContent, api.Patch("/things/1", "Idempotency-Key: abc", body).Code)
+	}()
+	<-started
+
+	resp := api.Patch("/things/1", "Idempotency-Key: abc", body)
+	assert.Equal(t, http.StatusConflict, resp.Code)
+	assert.Contains(t, resp.Body.String(), "in progress")
+
+	close(release)
+	wg.Wait()
+
+	resp = api.Patch("/things/1", "Idempotency-Key: abc", body)
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Equal(t, "true", resp.Header().Get("Idempotent-Replayed"))
+
+	// The key is required.
+	resp = api.Patch("/things/1", body)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Contains(t, resp.Body.String(), "Idempotency-Key header is required")
+}
+
+func TestOpenAPI(t *testing.T) {
+	_, api := humatest.New(t)
+	Enable(api, Config{})
+
+	handler := func(ctx context.Context, input *PaymentInput) (*PaymentOutput, error) {
+		return &PaymentOutput{}, nil
+	}
+	huma.Post(api, "/payments", handler, WithIdempotency())
+	huma.Post(api, "/other", handler)
+
+	op := api.OpenAPI().Paths["/payments"].Post
+	var param *huma.Param
+	for _, p := range op.Parameters {
+		if p.Name == HeaderName {
+			param = p
+		}
+	}
+	require.NotNil(t, param)
+	assert.Equal(t, "header", param.In)
+	assert.False(t, param.Required)
+	assert.Equal(t, "string", param.Schema.Type)
+	assert.Contains(t, op.Responses, "409")
+	assert.Contains(t, op.Responses, "422")
+	assert.Contains(t, op.Responses["409"].Content, "application/problem+json")
+
+	op = api.OpenAPI().Paths["/other"].Post
+	for _, p := range op.Parameters {
+		assert.NotEqual(t, HeaderName, p.Name)
+	}
+	assert.NotContains(t, op.Responses, "409")
+}
+
+func TestEnableAfterRegisterPanics(t *testing.T) {
+	_, api := humatest.New(t)
+	huma.Post(api, "/payments", func(ctx context.Context, input *PaymentInput) (*PaymentOutput, error) {
+		return &PaymentOutput{}, nil
+	}, WithIdempotency())
+	assert.Panics(t, func() {
+		Enable(api, Config{})
+	})
+}
+
+func TestReplayHeaders(t *testing.T) {
+	_, api := humatest.New(t)
+	api.UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+		ctx.SetHeader("Cache-Control", "no-store")
+		next(ctx)
+	})
+	Enable(api, Config{})
+
+	huma.Post(api, "/payments", func(ctx context.Context, input *PaymentInput) (*struct {
+		CacheControl string `header:"Cache-Control"`
+	}, error) {
+		return &struct {
+			CacheControl string `header:"Cache-Control"`
+		}{CacheControl: "private"}, nil
+	}, WithIdempotency())
+
+	body := map[string]any{"amount": 100}
+	resp := api.Post("/payments", "Idempotency-Key: abc", body)
+	assert.Equal(t, []string{"private"}, resp.Header().Values("Cache-Control"))
+
+	// Stored headers replace the ones set by middleware, just like the first
+	// time, rather than being added to them.
+	resp = api.Post("/payments", "Idempotency-Key: abc", body)
+	assert.Equal(t, "true", resp.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, []string{"private"}, resp.Header().Values("Cache-Control"))
+}
+
+func TestKeyScope(t *testing.T) {
+	store := NewMemoryStore()
+	versions := huma.NewVersions(humatest.NewAdapter(), "/versions")
+	calls := 0
+	var apis []humatest.TestAPI
+	for _, prefix := range []string{"/v1", "/v2"} {
+		config := huma.DefaultConfig("Test API", "1.0.0")
+		config.Components.SecuritySchemes = map[string]*huma.SecurityScheme{
+			"key": {Type: "apiKey", In: "header", Name: "X-API-Key"},
+		}
+		api := humatest.Wrap(t, versions.Add(prefix, config))
+		Enable(api, Config{Store: store})
+		huma.Post(api, "/payments", func(ctx context.Context, input *PaymentInput) (*struct{}, error) {
+			calls++
+			return nil, nil
+		}, WithIdempotency())
+		apis = append(apis, api)
+	}
+
+	body := map[string]any{"amount": 100}
+	apis[0].Post("/v1/payments", "Idempotency-Key: abc", "X-API-Key: alice", body)
+	resp := apis[0].Post("/v1/payments", "Idempotency-Key: abc", "X-API-Key: alice", body)
+	assert.Equal(t, "true", resp.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, 1, calls)
+
+	// Clients with different credentials never get each other's responses.
+	resp = apis[0].Post("/v1/payments", "Idempotency-Key: abc", "X-API-Key: bob", body)
+	assert.Empty(t, resp.Header().Get("Idempotent-Replayed"))
+	resp = apis[0].Post("/v1/payments", "Idempotency-Key: abc", "Authorization: Bearer alice", body)
+	assert.Empty(t, resp.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, 3, calls)
+
+	// Versions sharing a store don't collide.
+	resp = apis[1].Post("/v2/payments", "Idempotency-Key: abc", "X-API-Key: alice", body)
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Empty(t, resp.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, 4, calls)
+}
+
+// deadlineContext fails body reads with a timeout once a read deadline is
+// set, like a slow client would.
+type deadlineContext struct {
+	humaContext
+	deadline time.Time
+}
+
+func (c *deadlineContext) SetReadDeadline(t time.Time) error {
+	c.deadline = t
+	return nil
+}
+
+func (c *deadlineContext) BodyReader() io.Reader {
+	return deadlineReader{c}
+}
+
+type deadlineReader struct {
+	c *deadlineContext
+}
+
+func (r deadlineReader) Read(p []byte) (int, error) {
+	if !r.c.deadline.IsZero() {
+		return 0, os.ErrDeadlineExceeded
+	}
+	return 0, io.EOF
+}
+
+func TestBodyReadTimeout(t *testing.T) {
+	_, api := humatest.New(t)
+	var dc *deadlineContext
+	api.UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+		dc = &deadlineContext{humaContext: ctx}
+		next(dc)
+	})
+	Enable(api, Config{})
+
+	huma.Register(api, huma.Operation{
+		Method:          http.MethodPost,
+		Path:            "/payments",
+		BodyReadTimeout: time.Second,
+		Metadata:        map[string]any{MetadataKey: true},
+	}, func(ctx context.Context, input *PaymentInput) (*struct{}, error) {
+		return nil, nil
+	})
+
+	resp := api.Post("/payments", "Idempotency-Key: abc", map[string]any{"amount": 1})
+	assert.Equal(t, http.StatusRequestTimeout, resp.Code)
+	assert.False(t, dc.deadline.IsZero())
+}
@@ -0,0 +1,67 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-memory store, which is safe for concurrent use.
+// Expired records are removed periodically to limit memory use.
+type MemoryStore struct {
+	mu        sync.Mutex
+	records   map[string]*Record
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryStore creates a new in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		records: map[string]*Record{},
+		now:     time.Now,
+	}
+}
+
+// Begin claims the key, or returns the existing record for it.
+func (s *MemoryStore) Begin(ctx context.Context, key string, record *Record) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastSweep) > time.Minute {
+		s.sweep(now)
+	}
+
+	if existing := s.records[key]; existing != nil && now.Before(existing.Expires) {
+		return existing, nil
+	}
+	s.records[key] = record
+	return nil, nil
+}
+
+// Complete stores the response for the key.
+func (s *MemoryStore) Complete(ctx context.Context, key string, record *Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[key] = record
+	return nil
+}
+
+// Abort releases the key.
+func (s *MemoryStore) Abort(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
+
+// sweep removes expired records.
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, r := range s.records {
+		if !now.Before(r.Expires) {
+			delete(s.records, key)
+		}
+	}
+	s.lastSweep = now
+}
@@ -0,0 +1,426 @@
+// Package jwt verifies JSON Web Token (JWT) bearer tokens for use with the
+// `security` package. Tokens signed using HS256, RS256, ES256 or EdDSA are
+// verified against a set of keys, which can be loaded from a JWKS file and
+// rotated at runtime. The `exp`, `nbf`, `iss` and `aud` claims are checked,
+// and the `scope` or `scp` claim is matched against the scopes required by
+// the operation's security requirements.
+//
+//	keys, err := jwt.LoadJWKSFile("jwks.json")
+//	if err != nil {
+//		panic(err)
+//	}
+//	verifier := jwt.New(jwt.Config{
+//		Keys:     keys,
+//		Issuer:   "https://auth.example.com/",
+//		Audience: "my-api",
+//	})
+//	security.Enforce(api, security.Config{
+//		Bearer: verifier.Bearer,
+//		OAuth2: verifier.OAuth2,
+//	})
+//
+//	// Later, in a handler or resolver:
+//	claims := jwt.ClaimsFrom(ctx)
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2/security"
+)
+
+// Supported signing algorithms.
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+	ES256 = "ES256"
+	EdDSA = "EdDSA"
+)
+
+var (
+	// ErrMalformed is returned for tokens which can't be parsed.
+	ErrMalformed = errors.New("malformed token")
+
+	// ErrSignature is returned when no key verifies the token's signature.
+	ErrSignature = errors.New("invalid token signature")
+
+	// ErrExpired is returned for tokens past their `exp` time.
+	ErrExpired = errors.New("token is expired")
+
+	// ErrNotYetValid is returned for tokens before their `nbf` time.
+	ErrNotYetValid = errors.New("token is not valid yet")
+
+	// ErrIssuer is returned when the `iss` claim doesn't match.
+	ErrIssuer = errors.New("invalid token issuer")
+
+	// ErrAudience is returned when the `aud` claim doesn't match.
+	ErrAudience = errors.New("invalid token audience")
+)
+
+// Config describes how tokens are verified.
+type Config struct {
+	// Keys used to verify token signatures.
+	Keys *KeySet
+
+	// Issuer is the required `iss` claim, if set.
+	Issuer string
+
+	// Audience is required to be in the `aud` claim, if set.
+	Audience string
+
+	// Leeway allows for clock skew when checking the `exp` and `nbf` claims.
+	Leeway time.Duration
+
+	// Algorithms restricts the allowed signing algorithms. Defaults to all
+	// supported algorithms.
+	Algorithms []string
+
+	// Now returns the current time. Defaults to `time.Now`.
+	Now func() time.Time
+}
+
+// Claims are the verified claims of a token.
+type Claims struct {
+	Issuer    string
+	Subject   string
+	Audience  []string
+	ExpiresAt time.Time
+	NotBefore time.Time
+	IssuedAt  time.Time
+	ID        string
+
+	// Scopes from the `scope` or `scp` claim.
+	Scopes []string
+
+	raw []byte
+}
+
+// Decode the token's claims into `v`, e.g. a struct describing your custom
+// claims.
+func (c *Claims) Decode(v any) error {
+	return json.Unmarshal(c.raw, v)
+}
+
+// HasScopes returns whether the token was granted all of the scopes.
+func (c *Claims) HasScopes(scopes ...string) bool {
+	for _, s := range scopes {
+		found := false
+		for _, granted := range c.Scopes {
+			if granted == s {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Verifier verifies tokens and their claims.
+type Verifier struct {
+	config Config
+}
+
+// New creates a new token verifier.
+func New(config Config) *Verifier {
+	if config.Keys == nil {
+		config.Keys = NewKeySet()
+	}
+	if len(config.Algorithms) == 0 {
+		config.Algorithms = []string{HS256, RS256, ES256, EdDSA}
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+	return &Verifier{config: config}
+}
+
+// Verify a token's signature and claims, returning the claims.
+func (v *Verifier) Verify(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformed
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeJSON(parts[0], &header); err != nil {
+		return nil, err
+	}
+	allowed := false
+	for _, alg := range v.config.Algorithms {
+		if alg == header.Alg {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, fmt.Errorf("%w: algorithm %q not allowed", ErrSignature, header.Alg)
+	}
+
+	sig, err := decodeSegment(parts[2])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	signed := []byte(parts[0] + "." + parts[1])
+	verified := false
+	for _, key := range v.config.Keys.find(header.Kid, header.Alg) {
+		if verify(header.Alg, key.Key, signed, sig) {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, ErrSignature
+	}
+
+	payload, err := decodeSegment(parts[1])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	claims, err := parseClaims(payload)
+	if err != nil {
+		return nil, err
+	}
+	return claims, v.validate(claims)
+}
+
+// validate checks the registered claims.
+func (v *Verifier) validate(c *Claims) error {
+	now := v.config.Now()
+	if !c.ExpiresAt.IsZero() && now.After(c.ExpiresAt.Add(v.config.Leeway)) {
+		return ErrExpired
+	}
+	if !c.NotBefore.IsZero() && now.Add(v.config.Leeway).Before(c.NotBefore) {
+		return ErrNotYetValid
+	}
+	if v.config.Issuer != "" && c.Issuer != v.config.Issuer {
+		return ErrIssuer
+	}
+	if v.config.Audience != "" {
+		found := false
+		for _, aud := range c.Audience {
+			if aud == v.config.Audience {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return ErrAudience
+		}
+	}
+	return nil
+}
+
+// Bearer verifies a token for a `security.Config.Bearer` verifier. The
+// principal is the token's `*Claims`.
+func (v *Verifier) Bearer(ctx context.Context, scheme, token string) (any, error) {
+	return v.Verify(token)
+}
+
+// OAuth2 verifies a token for a `security.Config.OAuth2` verifier, checking
+// that it was granted the required scopes. The principal is the token's
+// `*Claims`.
+func (v *Verifier) OAuth2(ctx context.Context, scheme, token string, scopes []string) (any, error) {
+	claims, err := v.Verify(token)
+	if err != nil {
+		return nil, err
+	}
+	if !claims.HasScopes(scopes...) {
+		return nil, security.ErrInsufficientScope
+	}
+	return claims, nil
+}
+
+// ClaimsFrom returns the verified claims for the request, or `nil` if the
+// request was not authenticated with a token. In resolvers, pass the
+// request's context from `huma.Context.Context()`.
+func ClaimsFrom(ctx context.Context) *Claims {
+	if claims, ok := security.Principal(ctx).(*Claims); ok {
+		return claims
+	}
+	for _, p := range security.Principals(ctx) {
+		if claims, ok := p.(*Claims); ok {
+			return claims
+		}
+	}
+	return nil
+}
+
+// verify checks the signature using the algorithm and key.
+func verify(alg string, key any, signed, sig []byte) bool {
+	switch alg {
+	case HS256:
+		mac := hmac.New(sha256.New, key.([]byte))
+		mac.Write(signed)
+		return hmac.Equal(sig, mac.Sum(nil))
+	case RS256:
+		hash := sha256.Sum256(signed)
+		return rsa.VerifyPKCS1v15(key.(*rsa.PublicKey), crypto.SHA256, hash[:], sig) == nil
+	case ES256:
+		if len(sig) != 64 {
+			return false
+		}
+		hash := sha256.Sum256(signed)
+		r := new(big.Int).SetBytes(sig[:32])
+		s := new(big.Int).SetBytes(sig[32:])
+		return ecdsa.Verify(key.(*ecdsa.PublicKey), hash[:], r, s)
+	case EdDSA:
+		return ed25519.Verify(key.(ed25519.PublicKey), signed, sig)
+	}
+	return false
+}
+
+// parseClaims parses the registered claims from the token payload.
+func parseClaims(payload []byte) (*Claims, error) {
+	var raw struct {
+		Iss   string          `json:"iss"`
+		Sub   string          `json:"sub"`
+		Aud   json.RawMessage `json:"aud"`
+		Exp   *json.Number    `json:"exp"`
+		Nbf   *json.Number    `json:"nbf"`
+		Iat   *json.Number    `json:"iat"`
+		Jti   string          `json:"jti"`
+		Scope json.RawMessage `json:"scope"`
+		Scp   json.RawMessage `json:"scp"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	if err := dec.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+
+	c := &Claims{Issuer: raw.Iss, Subject: raw.Sub, ID: raw.Jti, raw: payload}
+	var err error
+	if c.Audience, err = stringOrList(raw.Aud); err != nil {
+		return nil, err
+	}
+	if c.Scopes, err = stringOrList(raw.Scope); err != nil {
+		return nil, err
+	}
+	if c.Scopes == nil {
+		if c.Scopes, err = stringOrList(raw.Scp); err != nil {
+			return nil, err
+		}
+	}
+	for _, t := range []struct {
+		n   *json.Number
+		dst *time.Time
+	}{{raw.Exp, &c.ExpiresAt}, {raw.Nbf, &c.NotBefore}, {raw.Iat, &c.IssuedAt}} {
+		if t.n == nil {
+			continue
+		}
+		f, err := t.n.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+		}
+		*t.dst = time.Unix(0, int64(f*float64(time.Second)))
+	}
+	return c, nil
+}
+
+// stringOrList parses a claim which is either a space-separated string or a
+// list of strings.
+func stringOrList(data json.RawMessage) ([]string, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		return strings.Fields(s), nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	return list, nil
+}
+
+func decodeJSON(segment string, v any) error {
+	data, err := decodeSegment(segment)
+	if err != nil {
+		return ErrMalformed
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	return nil
+}
+
+// Sign creates a signed token with the given claims, which can be any value
+// that marshals to a JSON object. The key is one of `[]byte` for HS256,
+// `*rsa.PrivateKey` for RS256, `*ecdsa.PrivateKey` for ES256 or
+// `ed25519.PrivateKey` for EdDSA. This is mostly useful for tests and
+// development, as tokens are usually issued by an authorization server.
+func Sign(alg, kid string, key any, claims any) (string, error) {
+	header := map[string]string{"alg": alg, "typ": "JWT"}
+	if kid != "" {
+		header["kid"] = kid
+	}
+	h, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	p, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signed := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(p)
+
+	var sig []byte
+	hash := sha256.Sum256([]byte(signed))
+	switch k := key.(type) {
+	case []byte:
+		if alg != HS256 {
+			return "", fmt.Errorf("algorithm %s does not match key type %T", alg, key)
+		}
+		mac := hmac.New(sha256.New, k)
+		mac.Write([]byte(signed))
+		sig = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		if alg != RS256 {
+			return "", fmt.Errorf("algorithm %s does not match key type %T", alg, key)
+		}
+		if sig, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, hash[:]); err != nil {
+			return "", err
+		}
+	case *ecdsa.PrivateKey:
+		if alg != ES256 {
+			return "", fmt.Errorf("algorithm %s does not match key type %T", alg, key)
+		}
+		r, s, err := ecdsa.Sign(rand.Reader, k, hash[:])
+		if err != nil {
+			return "", err
+		}
+		sig = make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+	case ed25519.PrivateKey:
+		if alg != EdDSA {
+			return "", fmt.Errorf("algorithm %s does not match key type %T", alg, key)
+		}
+		sig = ed25519.Sign(k, []byte(signed))
+	default:
+		return "", fmt.Errorf("unsupported key type %T", key)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
@@ -0,0 +1,288 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/danielgtaylor/huma/v2/security"
+)
+
+var now = time.Unix(1700000000, 0)
+
+type testKey struct {
+	alg     string
+	private any
+	public  any
+	jwk     map[string]string
+}
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func generateKeys(t *testing.T) []testKey {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	edPub, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	secret := []byte("super-secret-shared-key")
+
+	return []testKey{
+		{HS256, secret, secret, map[string]string{"kty": "oct", "k": b64(secret)}},
+		{RS256, rsaKey, &rsaKey.PublicKey, map[string]string{
+			"kty": "RSA",
+			"n":   b64(rsaKey.N.Bytes()),
+			"e":   b64(big.NewInt(int64(rsaKey.E)).Bytes()),
+		}},
+		{ES256, ecKey, &ecKey.PublicKey, map[string]string{
+			"kty": "EC",
+			"crv": "P-256",
+			"x":   b64(ecKey.X.FillBytes(make([]byte, 32))),
+			"y":   b64(ecKey.Y.FillBytes(make([]byte, 32))),
+		}},
+		{EdDSA, edKey, edPub, map[string]string{"kty": "OKP", "crv": "Ed25519", "x": b64(edPub)}},
+	}
+}
+
+func writeJWKS(t *testing.T, path string, keys ...testKey) {
+	set := struct {
+		Keys []map[string]string `json:"keys"`
+	}{}
+	for _, k := range keys {
+		jwk := map[string]string{"kid": k.alg, "alg": k.alg, "use": "sig"}
+		for name, value := range k.jwk {
+			jwk[name] = value
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	b, err := json.Marshal(set)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, b, 0o600))
+}
+
+func sign(t *testing.T, k testKey, claims map[string]any) string {
+	token, err := Sign(k.alg, k.alg, k.private, claims)
+	require.NoError(t, err)
+	return token
+}
+
+func TestVerifyAlgorithms(t *testing.T) {
+	keys := generateKeys(t)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, path, keys...)
+	ks, err := LoadJWKSFile(path)
+	require.NoError(t, err)
+	require.Len(t, ks.Keys(), 4)
+
+	v := New(Config{Keys: ks, Now: func() time.Time { return now }})
+	for _, k := range keys {
+		t.Run(k.alg, func(t *testing.T) {
+			claims, err := v.Verify(sign(t, k, map[string]any{"sub": "alice", "exp": now.Unix() + 60}))
+			require.NoError(t, err)
+			assert.Equal(t, "alice", claims.Subject)
+			assert.Equal(t, now.Add(time.Minute), claims.ExpiresAt)
+
+			// Tampered tokens are rejected.
+			token := sign(t, k, map[string]any{"sub": "alice"})
+			other := sign(t, k, map[string]any{"sub": "mallory"})
+			_, err = v.Verify(token[:len(token)-10] + other[len(other)-10:])
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestVerifyClaims(t *testing.T) {
+	k := generateKeys(t)[0]
+	v := New(Config{
+		Keys:     NewKeySet(Key{ID: k.alg, Key: k.public}),
+		Issuer:   "https://auth.example.com/",
+		Audience: "my-api",
+		Leeway:   5 * time.Second,
+		Now:      func() time.Time { return now },
+	})
+	valid := map[string]any{"iss": "https://auth.example.com/", "aud": []string{"other", "my-api"}}
+
+	claims := func(extra map[string]any) map[string]any {
+		c := map[string]any{}
+		for k, v := range valid {
+			c[k] = v
+		}
+		for k, v := range extra {
+			c[k] = v
+		}
+		return c
+	}
+
+	for _, item := range []struct {
+		name   string
+		claims map[string]any
+		err    error
+	}{
+		{"valid", claims(nil), nil},
+		{"aud string", claims(map[string]any{"aud": "my-api"}), nil},
+		{"exp within leeway", claims(map[string]any{"exp": now.Unix() - 3}), nil},
+		{"expired", claims(map[string]any{"exp": now.Unix() - 10}), ErrExpired},
+		{"nbf within leeway", claims(map[string]any{"nbf": now.Unix() + 3}), nil},
+		{"not yet valid", claims(map[string]any{"nbf": now.Unix() + 10}), ErrNotYetValid},
+		{"wrong issuer", claims(map[string]any{"iss": "https://evil.com/"}), ErrIssuer},
+		{"wrong audience", claims(map[string]any{"aud": "other"}), ErrAudience},
+		{"missing audience", map[string]any{"iss": "https://auth.example.com/"}, ErrAudience},
+	} {
+		t.Run(item.name, func(t *testing.T) {
+			_, err := v.Verify(sign(t, k, item.claims))
+			if item.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, item.err)
+			}
+		})
+	}
+
+	_, err := v.Verify("not-a-token")
+	assert.ErrorIs(t, err, ErrMalformed)
+}
+
+func TestScopes(t *testing.T) {
+	k := generateKeys(t)[0]
+	v := New(Config{Keys: NewKeySet(Key{Key: k.public})})
+
+	claims, err := v.Verify(sign(t, k, map[string]any{"scope": "read write"}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"read", "write"}, claims.Scopes)
+
+	claims, err = v.Verify(sign(t, k, map[string]any{"scp": []string{"read"}}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"read"}, claims.Scopes)
+	assert.True(t, claims.HasScopes("read"))
+	assert.False(t, claims.HasScopes("read", "write"))
+
+	_, err = v.OAuth2(context.Background(), "oauth", sign(t, k, map[string]any{"scp": "read"}), []string{"write"})
+	assert.ErrorIs(t, err, security.ErrInsufficientScope)
+}
+
+func TestAlgorithmConfusion(t *testing.T) {
+	keys := generateKeys(t)
+	rsaKey := keys[1]
+
+	// An HMAC token signed with the RSA public key bytes must not verify.
+	pub := rsaKey.public.(*rsa.PublicKey)
+	token, err := Sign(HS256, "", pub.N.Bytes(), map[string]any{"sub": "mallory"})
+	require.NoError(t, err)
+	v := New(Config{Keys: NewKeySet(Key{Key: rsaKey.public})})
+	_, err = v.Verify(token)
+	assert.ErrorIs(t, err, ErrSignature)
+
+	// Unsigned tokens are never accepted.
+	_, err = v.Verify(b64([]byte(`{"alg":"none"}`)) + "." + b64([]byte(`{}`)) + ".")
+	assert.ErrorIs(t, err, ErrSignature)
+
+	// Algorithms can be restricted.
+	v = New(Config{Keys: NewKeySet(Key{Key: rsaKey.public}), Algorithms: []string{ES256}})
+	_, err = v.Verify(sign(t, rsaKey, map[string]any{}))
+	assert.ErrorIs(t, err, ErrSignature)
+}
+
+func TestKeyRotation(t *testing.T) {
+	keys := generateKeys(t)
+	oldKey, newKey := keys[2], keys[3]
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, path, oldKey)
+	ks, err := LoadJWKSFile(path)
+	require.NoError(t, err)
+	v := New(Config{Keys: ks})
+
+	oldToken := sign(t, oldKey, map[string]any{})
+	newToken := sign(t, newKey, map[string]any{})
+	assert.NoError(t, func() error { _, err := v.Verify(oldToken); return err }())
+	assert.Error(t, func() error { _, err := v.Verify(newToken); return err }())
+
+	// Publish the new key alongside the old one.
+	writeJWKS(t, path, oldKey, newKey)
+	require.NoError(t, ks.LoadFile(path))
+	assert.NoError(t, func() error { _, err := v.Verify(oldToken); return err }())
+	assert.NoError(t, func() error { _, err := v.Verify(newToken); return err }())
+
+	// Retire the old key.
+	ks.Set(Key{ID: newKey.alg, Key: newKey.public})
+	assert.Error(t, func() error { _, err := v.Verify(oldToken); return err }())
+
+	// Bad files keep the current keys.
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+	assert.Error(t, ks.LoadFile(path))
+	assert.Len(t, ks.Keys(), 1)
+}
+
+type CustomClaims struct {
+	Org string `json:"org"`
+}
+
+type OrgResolver struct {
+	org string
+}
+
+func (r *OrgResolver) Resolve(ctx huma.Context) []error {
+	var custom CustomClaims
+	if claims := ClaimsFrom(ctx.Context()); claims != nil {
+		if err := claims.Decode(&custom); err != nil {
+			return []error{err}
+		}
+	}
+	r.org = custom.Org
+	return nil
+}
+
+func TestSecurity(t *testing.T) {
+	k := generateKeys(t)[1]
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.Components.SecuritySchemes = map[string]*huma.SecurityScheme{
+		"oauth": {Type: "oauth2", Flows: &huma.OAuthFlows{}},
+	}
+	_, api := humatest.New(t, config)
+
+	v := New(Config{Keys: NewKeySet(Key{ID: k.alg, Key: k.public}), Audience: "my-api"})
+	security.Enforce(api, security.Config{OAuth2: v.OAuth2})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-me",
+		Method:      http.MethodGet,
+		Path:        "/me",
+		Security:    []map[string][]string{{"oauth": {"read"}}},
+	}, func(ctx context.Context, input *struct {
+		OrgResolver
+	}) (*struct{ Body string }, error) {
+		return &struct{ Body string }{Body: ClaimsFrom(ctx).Subject + "@" + input.org}, nil
+	})
+
+	token := sign(t, k, map[string]any{"sub": "alice", "aud": "my-api", "scope": "read", "org": "acme"})
+	resp := api.Get("/me", "Authorization: Bearer "+token)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, `"alice@acme"`+"\n", resp.Body.String())
+
+	token = sign(t, k, map[string]any{"sub": "alice", "aud": "my-api"})
+	resp = api.Get("/me", "Authorization: Bearer "+token)
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+
+	token = sign(t, k, map[string]any{"sub": "alice", "aud": "other", "scope": "read"})
+	resp = api.Get("/me", "Authorization: Bearer "+token)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	assert.Contains(t, resp.Header().Get("WWW-Authenticate"), `error="invalid_token"`)
+}
@@ -0,0 +1,224 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Key is a public key (or shared secret) used to verify token signatures.
+type Key struct {
+	// ID is the key ID, matched against the `kid` header of tokens.
+	ID string
+
+	// Algorithm optionally restricts the key to a single signing algorithm,
+	// e.g. `RS256`. If empty, any algorithm matching the key type is allowed.
+	Algorithm string
+
+	// Key is one of `[]byte` for HS256, `*rsa.PublicKey` for RS256,
+	// `*ecdsa.PublicKey` using P-256 for ES256 or `ed25519.PublicKey` for
+	// EdDSA.
+	Key any
+}
+
+// supports returns whether the key can verify signatures using the
+// algorithm. This prevents e.g. an RSA public key from being used as an HMAC
+// secret.
+func (k Key) supports(alg string) bool {
+	if k.Algorithm != "" && k.Algorithm != alg {
+		return false
+	}
+	switch key := k.Key.(type) {
+	case []byte:
+		return alg == HS256
+	case *rsa.PublicKey:
+		return alg == RS256
+	case *ecdsa.PublicKey:
+		return alg == ES256 && key.Curve == elliptic.P256()
+	case ed25519.PublicKey:
+		return alg == EdDSA
+	}
+	return false
+}
+
+// KeySet is a set of keys which is safe for concurrent use. The keys can be
+// replaced at any time to rotate them, e.g. by reloading a JWKS file on a
+// timer. During a rotation, publish the new key alongside the old one until
+// all tokens signed with the old key have expired.
+type KeySet struct {
+	mu   sync.RWMutex
+	keys []Key
+}
+
+// NewKeySet creates a new key set with the given keys.
+func NewKeySet(keys ...Key) *KeySet {
+	return &KeySet{keys: append([]Key{}, keys...)}
+}
+
+// LoadJWKSFile creates a new key set from a JWKS file. See `KeySet.LoadFile`.
+func LoadJWKSFile(path string) (*KeySet, error) {
+	ks := NewKeySet()
+	if err := ks.LoadFile(path); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+// Keys returns the current keys.
+func (ks *KeySet) Keys() []Key {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	return append([]Key{}, ks.keys...)
+}
+
+// Set replaces all of the keys in the set.
+func (ks *KeySet) Set(keys ...Key) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.keys = append([]Key{}, keys...)
+}
+
+// LoadFile replaces the keys in the set with those from a JWKS file. If the
+// file can't be read or parsed, the current keys are kept.
+func (ks *KeySet) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	keys, err := ParseJWKS(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	ks.Set(keys...)
+	return nil
+}
+
+// find returns the keys which may verify a token with the given header. If
+// the token has a key ID, then only that key and keys without an ID are used.
+func (ks *KeySet) find(kid, alg string) []Key {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	found := []Key{}
+	for _, k := range ks.keys {
+		if kid != "" && k.ID != "" && k.ID != kid {
+			continue
+		}
+		if k.supports(alg) {
+			found = append(found, k)
+		}
+	}
+	return found
+}
+
+// jwk is a single JSON Web Key as defined by RFC 7517.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	K   string `json:"k"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// ParseJWKS parses a JSON Web Key Set like `{"keys": [...]}`. Keys which are
+// not for signatures (`use` other than `sig`) or of an unsupported type are
+// skipped.
+func ParseJWKS(data []byte) ([]Key, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+	keys := make([]Key, 0, len(set.Keys))
+	for i, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.parse()
+		if err != nil {
+			return nil, fmt.Errorf("key %d: %w", i, err)
+		}
+		if key != nil {
+			keys = append(keys, Key{ID: k.Kid, Algorithm: k.Alg, Key: key})
+		}
+	}
+	return keys, nil
+}
+
+// parse returns the public key for the JWK, or nil if the key type is not
+// supported.
+func (k jwk) parse() (any, error) {
+	switch k.Kty {
+	case "oct":
+		return decodeSegment(k.K)
+	case "RSA":
+		n, err := decodeSegment(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeSegment(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if len(n) == 0 || len(e) == 0 || len(e) > 4 {
+			return nil, errors.New("invalid RSA key")
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, nil
+		}
+		x, err := decodeSegment(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeSegment(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+		if !key.Curve.IsOnCurve(key.X, key.Y) {
+			return nil, errors.New("invalid EC key")
+		}
+		return key, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, nil
+		}
+		x, err := decodeSegment(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, nil
+}
+
+// decodeSegment decodes base64url data, with or without padding.
+func decodeSegment(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
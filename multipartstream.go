@@ -0,0 +1,237 @@
+package huma
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// FormPart is a single part of a streamed multipart form. Read the part's
+// contents before calling `NextPart` again. Reading more than the field's
+// `maxLength` returns an HTTP 413 error.
+type FormPart struct {
+	io.Reader
+
+	// Name of the form field, e.g. `file`.
+	Name string
+
+	// Filename as declared in the part, if any.
+	Filename string
+
+	// ContentType as declared in the part, or detected from its contents as a
+	// fallback.
+	ContentType string
+
+	// Index of the part within its form field, which is useful for fields
+	// accepting multiple files.
+	Index int
+}
+
+// MultipartFormStream reads a `multipart/form-data` request body one part at
+// a time without buffering it into memory or temporary files, which makes it
+// suitable for large uploads. The fields of `T` use the same tags as with
+// `MultipartFormFiles` to document the form and declare limits, which are
+// enforced as the parts are read:
+//
+//	type UploadForm struct {
+//		Avatar huma.FormFile   `form:"avatar" contentType:"image/png" maxLength:"1048576"`
+//		Photos []huma.FormFile `form:"photos" contentType:"image/*" maxItems:"5"`
+//	}
+//
+//	func(ctx context.Context, input *struct {
+//		RawBody huma.MultipartFormStream[UploadForm]
+//	}) (*struct{}, error) {
+//		for {
+//			part, err := input.RawBody.NextPart()
+//			if err == io.EOF {
+//				break
+//			}
+//			if err != nil {
+//				return nil, err
+//			}
+//			// Process `part` here...
+//		}
+//		return nil, nil
+//	}
+type MultipartFormStream[T any] struct {
+	reader    *multipart.Reader
+	mediaType *MediaType
+	counts    map[string]int
+	part      *multipart.Part
+	err       error
+}
+
+// multipartStreamer is used to set up a `MultipartFormStream` of any type.
+type multipartStreamer interface {
+	formType() reflect.Type
+	setup(r io.Reader, contentType string, mediaType *MediaType)
+}
+
+func (s *MultipartFormStream[T]) formType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func (s *MultipartFormStream[T]) setup(r io.Reader, contentType string, mediaType *MediaType) {
+	s.mediaType = mediaType
+	s.counts = map[string]int{}
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil || params["boundary"] == "" {
+		s.err = NewError(http.StatusBadRequest, "cannot read multipart form", http.ErrMissingBoundary)
+		return
+	}
+	s.reader = multipart.NewReader(r, params["boundary"])
+}
+
+// NextPart returns the next part of the form, or `io.EOF` once all parts
+// have been read. Errors are `StatusError` values which can be returned from
+// the handler as-is, for example when a field has too many files, the
+// content type is not allowed, or a required field is missing.
+func (s *MultipartFormStream[T]) NextPart() (*FormPart, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	if s.part != nil {
+		s.part.Close()
+		s.part = nil
+	}
+
+	part, err := s.reader.NextPart()
+	if err == io.EOF {
+		// Make sure all the required fields were sent.
+		errs := []error{}
+		for _, name := range s.mediaType.Schema.Required {
+			if s.counts[name] == 0 {
+				errs = append(errs, &ErrorDetail{Message: "Field required", Location: "body." + name})
+			}
+		}
+		if len(errs) > 0 {
+			s.err = NewError(http.StatusUnprocessableEntity, "validation failed", errs...)
+		} else {
+			s.err = io.EOF
+		}
+		return nil, s.err
+	}
+	if err != nil {
+		var se StatusError
+		if errors.As(err, &se) {
+			// E.g. the request body is too large or timed out.
+			s.err = se
+		} else {
+			s.err = NewError(http.StatusBadRequest, "cannot read multipart form", err)
+		}
+		return nil, s.err
+	}
+	s.part = part
+
+	name := part.FormName()
+	schema := s.mediaType.Schema.Properties[name]
+	if schema == nil {
+		s.err = NewError(http.StatusUnprocessableEntity, "validation failed", &ErrorDetail{
+			Message:  "Unexpected form field",
+			Location: "body." + name,
+		})
+		return nil, s.err
+	}
+
+	index := s.counts[name]
+	s.counts[name]++
+	location := "body." + name
+	tooMany := ""
+	if schema.Type == TypeArray {
+		location = fmt.Sprintf("body.%s[%d]", name, index)
+		if schema.MaxItems != nil && s.counts[name] > *schema.MaxItems {
+			tooMany = fmt.Sprintf("Too many files, expected at most %d", *schema.MaxItems)
+		}
+		schema = schema.Items
+	} else if s.counts[name] > 1 {
+		tooMany = "Multiple files received but only one was expected"
+	}
+	if tooMany != "" {
+		s.err = NewError(http.StatusUnprocessableEntity, "validation failed", &ErrorDetail{
+			Message:  tooMany,
+			Location: "body." + name,
+			Value:    s.counts[name],
+		})
+		return nil, s.err
+	}
+
+	// Check the content type, detecting it from the first few bytes if the
+	// client did not send one.
+	br := bufio.NewReader(part)
+	contentType := part.Header.Get("Content-Type")
+	if contentType == "" {
+		peek, _ := br.Peek(512)
+		contentType = http.DetectContentType(peek)
+	}
+	if encoding := s.mediaType.Encoding[name]; encoding != nil && isFileSchema(schema) {
+		if err := NewMimeTypeValidator(encoding).ValidateType(contentType, location); err != nil {
+			s.err = NewError(http.StatusUnprocessableEntity, "validation failed", err)
+			return nil, s.err
+		}
+	}
+
+	limit := int64(-1)
+	if schema.MaxLength != nil {
+		limit = int64(*schema.MaxLength)
+	}
+
+	return &FormPart{
+		Reader:      &formPartReader{r: br, limit: limit, location: location},
+		Name:        name,
+		Filename:    part.FileName(),
+		ContentType: contentType,
+		Index:       index,
+	}, nil
+}
+
+// isFileSchema returns whether the schema describes a file upload.
+func isFileSchema(s *Schema) bool {
+	return s != nil && s.Type == TypeString && s.Format == "binary"
+}
+
+// formPartReader limits how many bytes can be read from a form part.
+type formPartReader struct {
+	r        io.Reader
+	limit    int64
+	n        int64
+	location string
+}
+
+func (p *formPartReader) Read(b []byte) (int, error) {
+	if p.limit < 0 {
+		return p.r.Read(b)
+	}
+	if p.n > p.limit {
+		return 0, p.tooLarge()
+	}
+	if int64(len(b)) > p.limit-p.n+1 {
+		// Read at most one byte past the limit to detect going over it.
+		b = b[:p.limit-p.n+1]
+	}
+	n, err := p.r.Read(b)
+	p.n += int64(n)
+	if p.n > p.limit {
+		return n - int(p.n-p.limit), p.tooLarge()
+	}
+	return n, err
+}
+
+func (p *formPartReader) tooLarge() error {
+	return NewError(http.StatusRequestEntityTooLarge, "validation failed", &ErrorDetail{
+		Message:  fmt.Sprintf("File too large, expected size <= %d bytes", p.limit),
+		Location: p.location,
+	})
+}
+
+// isMultipartFormStream returns whether the type is a `MultipartFormStream`.
+func isMultipartFormStream(t reflect.Type) bool {
+	return strings.HasPrefix(t.Name(), "MultipartFormStream[") && reflect.PointerTo(t).Implements(multipartStreamerType)
+}
+
+var multipartStreamerType = reflect.TypeOf((*multipartStreamer)(nil)).Elem()
@@ -38,10 +38,17 @@ func SelectQValue(header string, allowed []string) string {
 		if len(parts) > 1 {
 			trimmed := strings.Trim(parts[1], " \t")
 			if strings.HasPrefix(trimmed, "q=") {
-				q, _ = strconv.ParseFloat(trimmed[2:], 64)
+				if parsed, err := strconv.ParseFloat(trimmed[2:], 64); err == nil {
+					q = parsed
+				}
 			}
 		}
 
+		if q <= 0 {
+			// A zero weight means the value is not acceptable.
+			continue
+		}
+
 		// Prefer the first one if there is a tie.
 		if q > bestQ || (q == bestQ && name == allowed[0]) {
 			bestQ = q
@@ -58,60 +65,60 @@ func SelectQValueFast(header string, allowed []string) string {
 	best := ""
 	bestQ := 0.0
 
-	name := ""
-	start := -1
-	end := 0
-
-	for pos, char := range header {
+	for len(header) > 0 {
 		// Format is like "a; q=0.5, b;q=1.0,c; q=0.3"
-		if char == ';' {
-			name = header[start : end+1]
-			start = -1
-			continue
+		item := header
+		if i := strings.IndexByte(header, ','); i != -1 {
+			item, header = header[:i], header[i+1:]
+		} else {
+			header = ""
 		}
 
-		if char == ',' || pos == len(header)-1 {
-			q := 1.0
-			if pos == len(header)-1 {
-				// This is the end, so it must be the name.
-				// Example: "application/yaml"
-				name = header[start : pos+1]
-			} else if name == "" {
-				// No name yet means we did not encounter a `;`.
-				// Example: "a, b, c"
-				name = header[start:pos]
-			} else {
-				if parsed, _ := strconv.ParseFloat(header[start+2:end+1], 64); parsed > 0 {
-					q = parsed
+		name := item
+		q := 1.0
+		if i := strings.IndexByte(item, ';'); i != -1 {
+			name = item[:i]
+
+			// Find the `q` param, e.g. in `application/foo;v=b3;q=0.7`.
+			params := item[i+1:]
+			for len(params) > 0 {
+				param := params
+				if j := strings.IndexByte(params, ';'); j != -1 {
+					param, params = params[:j], params[j+1:]
+				} else {
+					params = ""
 				}
-			}
-			start = -1
-
-			found := false
-			for _, n := range allowed {
-				if n == name {
-					found = true
-					break
+				param = strings.Trim(param, " \t")
+				if strings.HasPrefix(param, "q=") {
+					if parsed, err := strconv.ParseFloat(param[2:], 64); err == nil {
+						q = parsed
+					}
 				}
 			}
+		}
+		name = strings.Trim(name, " \t")
 
-			if !found {
-				// Skip formats we don't support.
-				continue
-			}
+		if q <= 0 {
+			// A zero weight means the value is not acceptable.
+			continue
+		}
 
-			if q > bestQ || (q == bestQ && name == allowed[0]) {
-				bestQ = q
-				best = name
+		found := false
+		for _, n := range allowed {
+			if n == name {
+				found = true
+				break
 			}
+		}
+
+		if !found {
+			// Skip formats we don't support.
 			continue
 		}
 
-		if char != ' ' && char != '\t' {
-			end = pos
-			if start == -1 {
-				start = pos
-			}
+		if q > bestQ || (q == bestQ && name == allowed[0]) {
+			bestQ = q
+			best = name
 		}
 	}
 
@@ -50,6 +50,20 @@ func TestNoMatchFast(t *testing.T) {
 	assert.Equal(t, "", SelectQValueFast("a; q=1.0, b;q=1.0,c; q=0.3", []string{"d", "e"}))
 }
 
+func TestAcceptFastLastQ(t *testing.T) {
+	assert.Equal(t, "br", SelectQValueFast("gzip;q=0.5, br;q=1", []string{"gzip", "br"}))
+}
+
+func TestAcceptFastMixed(t *testing.T) {
+	assert.Equal(t, "b", SelectQValueFast("a; q=0.5, b,c; q=0.3", []string{"a", "b", "c"}))
+}
+
+func TestAcceptZeroQ(t *testing.T) {
+	assert.Equal(t, "", SelectQValue("a;q=0", []string{"a", "b"}))
+	assert.Equal(t, "", SelectQValueFast("a;q=0", []string{"a", "b"}))
+	assert.Equal(t, "b", SelectQValueFast("a;q=0, b;q=0.1", []string{"a", "b"}))
+}
+
 var BenchResult string
 
 func BenchmarkMatch(b *testing.B) {
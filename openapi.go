@@ -829,14 +829,17 @@ type Operation struct {
 
 	// MaxBodyBytes is the maximum number of bytes to read from the request
 	// body. If not specified, the default is 1MB. Use -1 for unlimited. If
-	// the limit is reached, then an HTTP 413 error is returned.
+	// the limit is reached, then an HTTP 413 error is returned. Streamed
+	// `RawBody io.Reader` and `MultipartFormStream` bodies have no default
+	// limit, so only an explicitly set value is enforced.
 	MaxBodyBytes int64 `yaml:"-"`
 
 	// BodyReadTimeout is the maximum amount of time to wait for the request
 	// body to be read. If not specified, the default is 5 seconds. Use -1
 	// for unlimited. If the timeout is reached, then an HTTP 408 error is
 	// returned. This value supercedes the server's read timeout, and a value
-	// of -1 can unset the server's timeout.
+	// of -1 can unset the server's timeout. For streamed `RawBody io.Reader`
+	// bodies it is an idle timeout which is extended on every read.
 	BodyReadTimeout time.Duration `yaml:"-"`
 
 	// Errors is a list of HTTP status codes that the handler may return. If
@@ -847,6 +850,37 @@ type Operation struct {
 	// schema generated from the type returned by `huma.NewError()`.
 	Errors []int `yaml:"-"`
 
+	// ErrorModels registers a custom error type for specific HTTP status
+	// codes, e.g. a 409 response which includes the conflicting resource ID.
+	// The type returned by each factory is used as the schema for that
+	// response in the OpenAPI, and errors written by Huma itself for that
+	// status (e.g. validation errors) are created using the factory. Status
+	// codes listed here are added to `Errors` automatically.
+	//
+	//	ErrorModels: map[int]huma.ErrorFactory{
+	//		http.StatusConflict: func(status int, msg string, errs ...error) huma.StatusError {
+	//			return &ConflictError{Status: status, Detail: msg}
+	//		},
+	//	},
+	ErrorModels map[int]ErrorFactory `yaml:"-"`
+
+	// StrictStatus makes the operation return an HTTP 500 error instead of the
+	// handler's response when the output `Status` field is set to a status
+	// code which is not documented for the output, i.e. not the default
+	// status, listed in the field's `enum` tag, or set in `Responses` before
+	// registration. Error responses which are added automatically don't
+	// count. This is useful during development and testing to catch drift
+	// between the handler and its documentation. See also
+	// `Config.StrictStatus`.
+	StrictStatus bool `yaml:"-"`
+
+	// ValidateResponses validates the handler's output body and headers
+	// against the documented response before it is written, calling the
+	// given function with any errors. Overrides the API's
+	// `Config.ValidateResponses` setting. See `ResponseValidationLog` and
+	// `ResponseValidationFail`.
+	ValidateResponses ResponseValidator `yaml:"-"`
+
 	// SkipValidateParams disables validation of path, query, and header
 	// parameters. This can speed up request processing if you want to handle
 	// your own validation. Use with caution!
@@ -1455,6 +1489,10 @@ type OpenAPI struct {
 	// `AddOperation`. You may bypass this by directly writing to the `Paths`
 	// map instead.
 	OnAddOperation []AddOpFunc `yaml:"-"`
+
+	// config is the config of the API created with this OpenAPI, so that it is
+	// available to `Register` even if the API is wrapped, e.g. by a group.
+	config *Config
 }
 
 // AddOperation adds an operation to the OpenAPI. This is the preferred way to
@@ -5,10 +5,15 @@
 //	func handler(w http.ResponseWriter, r *http.Request) {
 //		// Get the value of the `key` query parameter.
 //		value := queryparam.Get(r.URL.RawQuery, "key")
+//
+//		// Get every value of a repeated `tag` query parameter.
+//		queryparam.Each(r.URL.RawQuery, "tag", func(value string) {
+//			fmt.Println(value)
+//		})
 //	}
 //
-// Note that this method does not support multiple values for the same key,
-// so using `val=1,2,3` is preferable to `val=1&val=2&val=3`.
+// Note that `Get` only returns the first value for a given key, so use
+// `Each` when multiple values like `val=1&val=2&val=3` are expected.
 package queryparam
 
 import (
@@ -16,19 +21,13 @@ import (
 	"strings"
 )
 
-// Get a query param by name without any dynamic allocations. For small numbers
-// of query params, it is faster to call this method multiple times than to
-// use `url.ParseQuery` and then call `Get` on the resulting `url.Values`.
-// This method does not support multiple values for the same key, so using
-// `val=1,2,3` is preferable to `val=1&val=2&val=3`.
-func Get(query, name string) string {
+// each calls `cb` for every value of the query param `name` in the order in
+// which they appear in the query string. Iteration stops early if `cb`
+// returns false.
+func each(query, name string, cb func(value string) bool) {
 	pos := 0
 	for pos < len(query) {
-		end := strings.IndexRune(query[pos:], '=')
-		if end == -1 {
-			end = strings.IndexRune(query[pos:], '&')
-		}
-
+		end := strings.IndexAny(query[pos:], "=&")
 		if end == -1 {
 			end = len(query)
 		} else {
@@ -38,17 +37,27 @@ func Get(query, name string) string {
 		ueName, _ := url.QueryUnescape(query[pos:end])
 		if ueName == name {
 			if end == len(query) || query[end] == '&' {
-				return "true"
-			}
-			pos = end + 1
-			end = strings.IndexRune(query[pos:], '&')
-			if end == -1 {
-				end = len(query)
+				if !cb("true") {
+					return
+				}
 			} else {
-				end += pos
+				pos = end + 1
+				end = strings.IndexRune(query[pos:], '&')
+				if end == -1 {
+					end = len(query)
+				} else {
+					end += pos
+				}
+				escaped, _ := url.QueryUnescape(query[pos:end])
+				if !cb(escaped) {
+					return
+				}
 			}
-			escaped, _ := url.QueryUnescape(query[pos:end])
-			return escaped
+			if end >= len(query) {
+				break
+			}
+			pos = end + 1
+			continue
 		}
 		tmp := pos
 		pos = strings.IndexRune(query[pos:], '&')
@@ -57,5 +66,28 @@ func Get(query, name string) string {
 		}
 		pos += tmp + 1
 	}
-	return ""
+}
+
+// Get a query param by name without any dynamic allocations. For small numbers
+// of query params, it is faster to call this method multiple times than to
+// use `url.ParseQuery` and then call `Get` on the resulting `url.Values`.
+// This method only returns the first value for a given key, see `Each` for
+// handling multiple values like `val=1&val=2&val=3`.
+func Get(query, name string) string {
+	result := ""
+	each(query, name, func(value string) bool {
+		result = value
+		return false
+	})
+	return result
+}
+
+// Each calls `cb` with every value of the query param `name` in the order in
+// which they appear, without any dynamic allocations. This supports repeated
+// keys like `val=1&val=2&val=3`, which is how exploded array params are sent.
+func Each(query, name string, cb func(value string)) {
+	each(query, name, func(value string) bool {
+		cb(value)
+		return true
+	})
 }
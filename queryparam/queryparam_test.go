@@ -133,6 +133,45 @@ func TestQuery(t *testing.T) {
 	}
 }
 
+func TestEach(t *testing.T) {
+	for _, item := range []struct {
+		query    string
+		name     string
+		expected []string
+	}{
+		{"foo=bar", "foo", []string{"bar"}},
+		{"foo=bar&baz=123", "missing", nil},
+		{"tag=a&foo=bar&tag=b&tag=c", "tag", []string{"a", "b", "c"}},
+		{"tag=a%2Cb&tag", "tag", []string{"a,b", "true"}},
+		{"tag&tag=&tag=x", "tag", []string{"true", "", "x"}},
+	} {
+		t.Run(item.query+"/"+item.name, func(t *testing.T) {
+			var values []string
+			Each(item.query, item.name, func(value string) {
+				values = append(values, value)
+			})
+			assert.Equal(t, item.expected, values)
+		})
+	}
+}
+
+func TestParseQueryEach(t *testing.T) {
+	for _, test := range parseTests {
+		if !test.ok {
+			continue
+		}
+		t.Run(test.query, func(t *testing.T) {
+			for k, v := range test.out {
+				var values []string
+				Each(test.query, k, func(value string) {
+					values = append(values, value)
+				})
+				assert.Equal(t, v, values)
+			}
+		})
+	}
+}
+
 var Result string
 
 func BenchmarkNewQuery(b *testing.B) {
@@ -150,6 +189,15 @@ func BenchmarkStdQuery(b *testing.B) {
 	}
 }
 
+func BenchmarkNewQueryEach(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Each("tag=a&foo=bar&tag=b&tag=c", "tag", func(value string) {
+			Result = value
+		})
+	}
+}
+
 var Foo, Baz, Num, Float, Boolean string
 var Values url.Values
 
@@ -0,0 +1,224 @@
+// Package ratelimit provides rate limiting for Huma operations using a token
+// bucket per operation and client. Limits are set per operation via its
+// metadata, e.g. using a group modifier, and limited operations document
+// their `429 Too Many Requests` response and rate limit headers in the
+// OpenAPI.
+//
+//	api := humachi.New(router, huma.DefaultConfig("My API", "1.0.0"))
+//	ratelimit.Enable(api, ratelimit.Config{})
+//
+//	// Register operations *after* enabling rate limiting.
+//	huma.Get(api, "/things", listThings, ratelimit.WithLimit(ratelimit.Limit{
+//		Requests: 100,
+//		Period:   time.Minute,
+//	}))
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// MetadataKey is the operation metadata key used to set the operation's
+// limit. The value is a `Limit`, or `false` to disable the default limit.
+const MetadataKey = "ratelimit"
+
+// Limit allows a number of requests per period for each client. Requests are
+// allowed in bursts up to the full limit, and the allowance is refilled
+// evenly over the period.
+type Limit struct {
+	// Requests allowed per period.
+	Requests int
+
+	// Period over which the requests are allowed.
+	Period time.Duration
+
+	// Bucket optionally names a bucket which is shared by all operations with
+	// the same bucket name, e.g. for all operations in a group. Defaults to
+	// the operation ID, so each operation is limited separately.
+	Bucket string
+}
+
+// Result is the outcome of taking a request from a client's allowance.
+type Result struct {
+	// Allowed is whether the request may proceed.
+	Allowed bool
+
+	// Remaining is the number of requests left in the allowance.
+	Remaining int
+
+	// Reset is the time until the allowance is completely refilled.
+	Reset time.Duration
+
+	// RetryAfter is the time until the next request is allowed, if the
+	// request was not allowed.
+	RetryAfter time.Duration
+}
+
+// Store keeps track of the allowance of each client. Implement this to share
+// rate limits between several instances of a service, e.g. using Redis.
+type Store interface {
+	// Take a request from the allowance for the key, which is a combination of
+	// the limit's bucket and the client.
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// Config describes how requests are rate limited.
+type Config struct {
+	// Default is the limit for operations which do not set their own. If
+	// zero, only operations which set a limit are rate limited.
+	Default Limit
+
+	// Store keeps track of allowances. Defaults to a new in-memory store.
+	Store Store
+
+	// Key identifies the client making the request. Defaults to `RemoteIP`.
+	// Return e.g. the authenticated principal's ID to limit per principal.
+	Key func(ctx huma.Context) string
+}
+
+// WithLimit sets the rate limit of an operation. It can be passed to the
+// convenience methods like `huma.Get` or used as a group modifier.
+func WithLimit(limit Limit) func(op *huma.Operation) {
+	return func(op *huma.Operation) {
+		if op.Metadata == nil {
+			op.Metadata = map[string]any{}
+		}
+		op.Metadata[MetadataKey] = limit
+	}
+}
+
+// RemoteIP returns the IP address of the client, without the port.
+func RemoteIP(ctx huma.Context) string {
+	addr := ctx.RemoteAddr()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// limitFor returns the limit for the operation, if any.
+func (c *Config) limitFor(op *huma.Operation) *Limit {
+	limit := c.Default
+	if op != nil && op.Metadata != nil {
+		switch v := op.Metadata[MetadataKey].(type) {
+		case Limit:
+			limit = v
+		case *Limit:
+			limit = *v
+		case bool:
+			if !v {
+				return nil
+			}
+		}
+	}
+	if limit.Requests <= 0 || limit.Period <= 0 {
+		return nil
+	}
+	if limit.Bucket == "" && op != nil {
+		limit.Bucket = op.OperationID
+	}
+	return &limit
+}
+
+// Enable rate limiting for the API. A middleware checks the limit for each
+// request, and it panics if operations have already been registered. To limit
+// per authenticated principal, enable this after enabling authentication so
+// that the principal is available to the `Key` function.
+//
+// If the store returns an error, the request is allowed.
+func Enable(api huma.API, config Config) {
+	huma.RequireNoOperations(api, "ratelimit.Enable")
+
+	c := &config
+	if c.Store == nil {
+		c.Store = NewMemoryStore()
+	}
+	if c.Key == nil {
+		c.Key = RemoteIP
+	}
+
+	oapi := api.OpenAPI()
+	oapi.OnAddOperation = append(oapi.OnAddOperation, func(oapi *huma.OpenAPI, op *huma.Operation) {
+		if c.limitFor(op) != nil {
+			document(oapi, op)
+		}
+	})
+
+	api.UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+		limit := c.limitFor(ctx.Operation())
+		if limit == nil {
+			next(ctx)
+			return
+		}
+
+		result, err := c.Store.Take(ctx.Context(), limit.Bucket+":"+c.Key(ctx), *limit)
+		if err != nil {
+			next(ctx)
+			return
+		}
+
+		ctx.SetHeader("RateLimit-Limit", strconv.Itoa(limit.Requests))
+		ctx.SetHeader("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		ctx.SetHeader("RateLimit-Reset", seconds(result.Reset))
+		if !result.Allowed {
+			ctx.SetHeader("Retry-After", seconds(result.RetryAfter))
+			huma.WriteErr(api, ctx, http.StatusTooManyRequests, "rate limit exceeded")
+			return
+		}
+		next(ctx)
+	})
+}
+
+// seconds formats a duration as a whole number of seconds, rounded up.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
+
+// headers are the documented rate limit response headers.
+var headers = map[string]string{
+	"RateLimit-Limit":     "Number of requests allowed per period.",
+	"RateLimit-Remaining": "Number of requests remaining in the current period.",
+	"RateLimit-Reset":     "Number of seconds until the allowance is fully restored.",
+}
+
+// document adds the rate limit headers and a `429 Too Many Requests` response
+// to the operation.
+func document(oapi *huma.OpenAPI, op *huma.Operation) {
+	if op.Responses == nil {
+		op.Responses = map[string]*huma.Response{}
+	}
+	if op.Responses["429"] == nil {
+		op.Responses["429"] = huma.ErrorResponse(oapi.Components.Schemas, op, http.StatusTooManyRequests)
+	}
+
+	for code, resp := range op.Responses {
+		if resp.Ref != "" || (code != "429" && (len(code) != 3 || code[0] != '2')) {
+			continue
+		}
+		if resp.Headers == nil {
+			resp.Headers = map[string]*huma.Param{}
+		}
+		for name, desc := range headers {
+			addHeader(resp, name, desc)
+		}
+		if code == "429" {
+			addHeader(resp, "Retry-After", "Number of seconds to wait before making another request.")
+		}
+	}
+}
+
+func addHeader(resp *huma.Response, name, desc string) {
+	if resp.Headers[name] == nil {
+		resp.Headers[name] = &huma.Param{
+			Description: desc,
+			Schema:      &huma.Schema{Type: "integer"},
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+)
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+	limit := Limit{Requests: 2, Period: 10 * time.Second}
+
+	r, _ := s.Take(context.Background(), "a", limit)
+	assert.Equal(t, Result{Allowed: true, Remaining: 1, Reset: 5 * time.Second}, r)
+	r, _ = s.Take(context.Background(), "a", limit)
+	assert.Equal(t, Result{Allowed: true, Remaining: 0, Reset: 10 * time.Second}, r)
+	r, _ = s.Take(context.Background(), "a", limit)
+	assert.Equal(t, Result{Allowed: false, Remaining: 0, Reset: 10 * time.Second, RetryAfter: 5 * time.Second}, r)
+
+	// Other keys have their own allowance.
+	r, _ = s.Take(context.Background(), "b", limit)
+	assert.True(t, r.Allowed)
+
+	// The allowance is refilled over time.
+	now = now.Add(5 * time.Second)
+	r, _ = s.Take(context.Background(), "a", limit)
+	assert.True(t, r.Allowed)
+	r, _ = s.Take(context.Background(), "a", limit)
+	assert.False(t, r.Allowed)
+
+	// Full buckets are eventually removed.
+	now = now.Add(time.Hour)
+	s.Take(context.Background(), "c", limit)
+	assert.Len(t, s.buckets, 1)
+}
+
+type errStore struct{}
+
+func (errStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	return Result{}, errors.New("unavailable")
+}
+
+func register(api huma.API, path string, modifiers ...func(op *huma.Operation)) {
+	huma.Get(api, path, func(ctx context.Context, input *struct{}) (*struct{ Body string }, error) {
+		return &struct{ Body string }{Body: "ok"}, nil
+	}, modifiers...)
+}
+
+func TestEnable(t *testing.T) {
+	_, api := humatest.New(t)
+	Enable(api, Config{
+		Key: func(ctx huma.Context) string {
+			if user := ctx.Header("X-User"); user != "" {
+				return user
+			}
+			return RemoteIP(ctx)
+		},
+	})
+
+	register(api, "/limited", WithLimit(Limit{Requests: 2, Period: time.Minute}))
+	register(api, "/unlimited")
+
+	grp := huma.NewGroup(api, "/grp")
+	grp.UseModifier(WithLimit(Limit{Requests: 1, Period: time.Minute, Bucket: "grp"}))
+	register(grp, "/a")
+	register(grp, "/b")
+
+	resp := api.Get("/limited")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "2", resp.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", resp.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", resp.Header().Get("RateLimit-Reset"))
+
+	assert.Equal(t, http.StatusOK, api.Get("/limited").Code)
+
+	resp = api.Get("/limited")
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+	assert.Equal(t, "0", resp.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", resp.Header().Get("Retry-After"))
+	assert.Equal(t, "application/problem+json", resp.Header().Get("Content-Type"))
+	assert.Contains(t, resp.Body.String(), "rate limit exceeded")
+
+	// Other principals have their own allowance.
+	assert.Equal(t, http.StatusOK, api.Get("/limited", "X-User: alice").Code)
+
+	// Operations without a limit are not limited.
+	for i := 0; i < 5; i++ {
+		resp = api.Get("/unlimited")
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Empty(t, resp.Header().Get("RateLimit-Limit"))
+	}
+
+	// Operations in the group share a bucket.
+	assert.Equal(t, http.StatusOK, api.Get("/grp/a").Code)
+	assert.Equal(t, http.StatusTooManyRequests, api.Get("/grp/b").Code)
+}
+
+func TestDefaultLimit(t *testing.T) {
+	_, api := humatest.New(t)
+	Enable(api, Config{Default: Limit{Requests: 1, Period: time.Hour}})
+
+	register(api, "/default")
+	register(api, "/disabled", func(op *huma.Operation) {
+		op.Metadata = map[string]any{MetadataKey: false}
+	})
+
+	assert.Equal(t, http.StatusOK, api.Get("/default").Code)
+	assert.Equal(t, http.StatusTooManyRequests, api.Get("/default").Code)
+	assert.Equal(t, http.StatusOK, api.Get("/disabled").Code)
+	assert.Equal(t, http.StatusOK, api.Get("/disabled").Code)
+
+	assert.NotNil(t, api.OpenAPI().Paths["/default"].Get.Responses["429"])
+	assert.Nil(t, api.OpenAPI().Paths["/disabled"].Get.Responses["429"])
+}
+
+func TestStoreErrorAllows(t *testing.T) {
+	_, api := humatest.New(t)
+	Enable(api, Config{Default: Limit{Requests: 1, Period: time.Hour}, Store: errStore{}})
+	register(api, "/test")
+
+	assert.Equal(t, http.StatusOK, api.Get("/test").Code)
+	assert.Equal(t, http.StatusOK, api.Get("/test").Code)
+}
+
+func TestOpenAPI(t *testing.T) {
+	_, api := humatest.New(t)
+	Enable(api, Config{})
+	register(api, "/limited", WithLimit(Limit{Requests: 10, Period: time.Second}))
+	register(api, "/unlimited")
+
+	op := api.OpenAPI().Paths["/limited"].Get
+	tooMany := op.Responses["429"]
+	require.NotNil(t, tooMany)
+	assert.Contains(t, tooMany.Content, "application/problem+json")
+	assert.Equal(t, "#/components/schemas/ErrorModel", tooMany.Content["application/problem+json"].Schema.Ref)
+	assert.Contains(t, tooMany.Headers, "Retry-After")
+	assert.Contains(t, tooMany.Headers, "RateLimit-Reset")
+	assert.Contains(t, op.Responses["200"].Headers, "RateLimit-Remaining")
+	assert.NotContains(t, op.Responses["200"].Headers, "Retry-After")
+
+	op = api.OpenAPI().Paths["/unlimited"].Get
+	assert.Nil(t, op.Responses["429"])
+	assert.Empty(t, op.Responses["200"].Headers)
+}
+
+func TestEnableAfterRegisterPanics(t *testing.T) {
+	_, api := humatest.New(t)
+	register(api, "/early", WithLimit(Limit{Requests: 1, Period: time.Minute}))
+	assert.Panics(t, func() {
+		Enable(api, Config{})
+	})
+}
@@ -0,0 +1,133 @@
+package huma
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// ResponseValidator handles errors found when validating a handler's output
+// against its documented response, which means the implementation has
+// drifted from the OpenAPI. Returning an error replaces the response with
+// that error, otherwise the response is written as-is.
+//
+//	config := huma.DefaultConfig("My API", "1.0.0")
+//	config.ValidateResponses = huma.ResponseValidationFail
+type ResponseValidator func(ctx Context, status int, errs []error) error
+
+// ResponseValidationLog is a `ResponseValidator` which logs any errors using
+// the standard library `log` package and then writes the response as-is.
+func ResponseValidationLog(ctx Context, status int, errs []error) error {
+	op := ctx.Operation()
+	for _, err := range errs {
+		log.Printf("response validation failed for %s %s %d: %s", op.Method, op.Path, status, err)
+	}
+	return nil
+}
+
+// ResponseValidationFail is a `ResponseValidator` which replaces the response
+// with an HTTP 500 error describing what was wrong with it. This is useful
+// during development and in tests to catch contract drift.
+func ResponseValidationFail(ctx Context, status int, errs []error) error {
+	return NewError(http.StatusInternalServerError, "response validation failed", errs...)
+}
+
+// validateOutput validates the handler's output headers and body against the
+// documented response for `status` and returns any errors found. The output
+// is validated before any transformers run.
+func validateOutput(registry Registry, op *Operation, formatTypes []string, status int, vo reflect.Value, outHeaders *findResult[*headerInfo], outBodyIndex int) []error {
+	// The `default` response describes errors, so it is not used here.
+	resp := op.Responses[strconv.Itoa(status)]
+	if resp == nil {
+		return []error{&ErrorDetail{
+			Message: fmt.Sprintf("undocumented response status %d", status),
+		}}
+	}
+
+	pb := NewPathBuffer([]byte{}, 0)
+	res := &ValidateResult{}
+
+	outHeaders.Every(vo, func(f reflect.Value, info *headerInfo) {
+		if f.Kind() == reflect.Pointer {
+			if f.IsNil() {
+				return
+			}
+			f = f.Elem()
+		}
+		if (f.Kind() == reflect.String && f.String() == "") || (f.Type() == timeType && f.Interface().(time.Time).IsZero()) {
+			// Empty headers are not sent.
+			return
+		}
+		header := resp.Headers[info.Name]
+		if header == nil || header.Schema == nil {
+			return
+		}
+		pb.Reset()
+		pb.Push("header")
+		pb.Push(info.Name)
+		if f.Kind() == reflect.Slice {
+			// Each item is written as a separate header value, so the schema
+			// describes the items.
+			for i := 0; i < f.Len(); i++ {
+				pb.PushIndex(i)
+				validateOutputValue(registry, header.Schema, pb, headerValue(f.Index(i)), res)
+				pb.Pop()
+			}
+			return
+		}
+		validateOutputValue(registry, header.Schema, pb, headerValue(f), res)
+	})
+
+	if outBodyIndex != -1 {
+		body := vo.Field(outBodyIndex).Interface()
+		switch body.(type) {
+		case []byte, func(Context):
+			// Raw and streamed bodies are not validated.
+		default:
+			var schema *Schema
+			for _, ct := range formatTypes {
+				if mt := resp.Content[ct]; mt != nil && mt.Schema != nil {
+					schema = mt.Schema
+					break
+				}
+			}
+			if schema != nil {
+				pb.Reset()
+				pb.Push("body")
+				validateOutputValue(registry, schema, pb, body, res)
+			}
+		}
+	}
+
+	return res.Errors
+}
+
+// headerValue returns the value of an output header as it will be written,
+// which is the result of calling `String()` for `fmt.Stringer` types.
+func headerValue(f reflect.Value) any {
+	if f.Type() != timeType && f.CanAddr() {
+		if s, ok := f.Addr().Interface().(fmt.Stringer); ok {
+			return s.String()
+		}
+	}
+	return f.Interface()
+}
+
+// validateOutputValue converts a Go value into its generic JSON form, e.g.
+// `map[string]any`, and validates it against the schema.
+func validateOutputValue(registry Registry, schema *Schema, pb *PathBuffer, value any, res *ValidateResult) {
+	var generic any
+	b, err := json.Marshal(value)
+	if err == nil {
+		err = json.Unmarshal(b, &generic)
+	}
+	if err != nil {
+		res.Add(pb, value, "cannot marshal value: "+err.Error())
+		return
+	}
+	Validate(registry, schema, pb, ModeReadFromServer, generic, res)
+}
@@ -550,27 +550,59 @@ func SchemaFromField(registry Registry, f reflect.StructField, hint string) *Sch
 		}
 	}
 
-	fs.Minimum = floatTag(f, "minimum")
-	fs.ExclusiveMinimum = floatTag(f, "exclusiveMinimum")
-	fs.Maximum = floatTag(f, "maximum")
-	fs.ExclusiveMaximum = floatTag(f, "exclusiveMaximum")
-	fs.MultipleOf = floatTag(f, "multipleOf")
-	fs.MinLength = intTag(f, "minLength")
-	fs.MaxLength = intTag(f, "maxLength")
-	fs.Pattern = f.Tag.Get("pattern")
-	fs.PatternDescription = f.Tag.Get("patternDescription")
+	// Only override validation set by the type itself, e.g. via a
+	// `SchemaProvider`, when the field sets the corresponding tag.
+	if v := floatTag(f, "minimum"); v != nil {
+		fs.Minimum = v
+	}
+	if v := floatTag(f, "exclusiveMinimum"); v != nil {
+		fs.ExclusiveMinimum = v
+	}
+	if v := floatTag(f, "maximum"); v != nil {
+		fs.Maximum = v
+	}
+	if v := floatTag(f, "exclusiveMaximum"); v != nil {
+		fs.ExclusiveMaximum = v
+	}
+	if v := floatTag(f, "multipleOf"); v != nil {
+		fs.MultipleOf = v
+	}
+	if v := intTag(f, "minLength"); v != nil {
+		fs.MinLength = v
+	}
+	if v := intTag(f, "maxLength"); v != nil {
+		fs.MaxLength = v
+	}
+	if v := f.Tag.Get("pattern"); v != "" {
+		fs.Pattern = v
+	}
+	if v := f.Tag.Get("patternDescription"); v != "" {
+		fs.PatternDescription = v
+	}
 	if _, ok := f.Tag.Lookup("minItems"); ok {
 		fs.MinItems = intTag(f, "minItems")
 	}
 	if _, ok := f.Tag.Lookup("maxItems"); ok {
 		fs.MaxItems = intTag(f, "maxItems")
 	}
-	fs.UniqueItems = boolTag(f, "uniqueItems")
-	fs.MinProperties = intTag(f, "minProperties")
-	fs.MaxProperties = intTag(f, "maxProperties")
-	fs.ReadOnly = boolTag(f, "readOnly")
-	fs.WriteOnly = boolTag(f, "writeOnly")
-	fs.Deprecated = boolTag(f, "deprecated")
+	if _, ok := f.Tag.Lookup("uniqueItems"); ok {
+		fs.UniqueItems = boolTag(f, "uniqueItems")
+	}
+	if v := intTag(f, "minProperties"); v != nil {
+		fs.MinProperties = v
+	}
+	if v := intTag(f, "maxProperties"); v != nil {
+		fs.MaxProperties = v
+	}
+	if _, ok := f.Tag.Lookup("readOnly"); ok {
+		fs.ReadOnly = boolTag(f, "readOnly")
+	}
+	if _, ok := f.Tag.Lookup("writeOnly"); ok {
+		fs.WriteOnly = boolTag(f, "writeOnly")
+	}
+	if _, ok := f.Tag.Lookup("deprecated"); ok {
+		fs.Deprecated = boolTag(f, "deprecated")
+	}
 	fs.PrecomputeMessages()
 
 	return fs
@@ -1228,3 +1228,22 @@ func TestSchemaTransformer(t *testing.T) {
 	updateSchema2 := huma.SchemaFromType(r, reflect.TypeOf(ExampleUpdateStruct{}))
 	validateSchema(updateSchema2)
 }
+
+type PatternProvider string
+
+func (p PatternProvider) Schema(r huma.Registry) *huma.Schema {
+	return &huma.Schema{Type: huma.TypeString, Pattern: "^[a-z]+$", MaxLength: Ptr(10)}
+}
+
+func TestSchemaFromFieldKeepsProviderValidation(t *testing.T) {
+	r := huma.NewMapRegistry("#/components/schemas/", huma.DefaultSchemaNamer)
+
+	f, _ := reflect.TypeOf(struct {
+		Value PatternProvider `json:"value" minLength:"2"`
+	}{}).FieldByName("Value")
+
+	s := huma.SchemaFromField(r, f, "")
+	assert.Equal(t, "^[a-z]+$", s.Pattern)
+	assert.Equal(t, Ptr(10), s.MaxLength)
+	assert.Equal(t, Ptr(2), s.MinLength)
+}
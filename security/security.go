@@ -0,0 +1,391 @@
+// Package security enforces the security requirements declared in the
+// OpenAPI at runtime, so authentication can't drift from the documentation.
+// Each operation's `Security` requirements (or the global ones if the
+// operation has none) are checked before the handler runs, extracting
+// credentials as described by the referenced `SecuritySchemes` and passing
+// them to pluggable verifiers for each scheme type.
+//
+//	config := huma.DefaultConfig("My API", "1.0.0")
+//	config.Components.SecuritySchemes = map[string]*huma.SecurityScheme{
+//		"bearer": {Type: "http", Scheme: "bearer"},
+//	}
+//	api := humachi.New(router, config)
+//	security.Enforce(api, security.Config{
+//		Bearer: func(ctx context.Context, scheme, token string) (any, error) {
+//			return lookupUser(ctx, token)
+//		},
+//	})
+//
+//	// Register operations *after* enabling enforcement.
+//	huma.Register(api, huma.Operation{
+//		Security: []map[string][]string{{"bearer": {}}},
+//		...
+//	}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+//		user := security.Principal(ctx).(*User)
+//		...
+//	})
+package security
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// ErrInsufficientScope can be returned by an OAuth2 verifier when the token is
+// valid but was not granted the required scopes. It results in a
+// `403 Forbidden` response rather than a `401 Unauthorized`.
+var ErrInsufficientScope = errors.New("insufficient scope")
+
+// Config provides the verifiers for each type of security scheme. Each
+// verifier is passed the name of the security scheme and the credentials from
+// the request, and returns the authenticated principal, e.g. a user, or an
+// error if the credentials are invalid. Errors which are a `huma.StatusError`
+// with a 403 status result in a `403 Forbidden` response, all other errors
+// result in a `401 Unauthorized` response.
+type Config struct {
+	// APIKey verifies keys for `apiKey` schemes, which are read from the
+	// header, query param or cookie given by the scheme.
+	APIKey func(ctx context.Context, scheme, key string) (any, error)
+
+	// Basic verifies credentials for `http` schemes using `basic` auth.
+	Basic func(ctx context.Context, scheme, username, password string) (any, error)
+
+	// Bearer verifies tokens for `http` schemes using `bearer` auth.
+	Bearer func(ctx context.Context, scheme, token string) (any, error)
+
+	// OAuth2 verifies bearer tokens for `oauth2` and `openIdConnect` schemes.
+	// It must check that the token was granted all of the required scopes, and
+	// should return `ErrInsufficientScope` if not.
+	OAuth2 func(ctx context.Context, scheme, token string, scopes []string) (any, error)
+
+	// Realm is sent in `WWW-Authenticate` challenges. Defaults to the API
+	// title.
+	Realm string
+}
+
+type principalsKey struct{}
+
+// authError describes why a security scheme could not be verified.
+type authError struct {
+	scheme  string
+	status  int
+	msg     string
+	invalid bool // credentials were sent but rejected
+	scopes  []string
+}
+
+// Enforce the security requirements of the API's operations. A middleware
+// verifies the credentials for each request, and it panics if operations have
+// already been registered as they would be left unprotected. Operations which
+// reference an unknown security scheme, or a scheme without a configured
+// verifier, cause a panic when they are added to the OpenAPI.
+//
+// Requirements are evaluated with OpenAPI semantics: any one of the listed
+// requirements must be satisfied, and a requirement is satisfied when all of
+// its schemes are verified. An empty requirement `{}` allows anonymous
+// access.
+//
+// Operations which don't allow anonymous access document their
+// `401 Unauthorized` and `403 Forbidden` responses.
+func Enforce(api huma.API, config Config) {
+	huma.RequireNoOperations(api, "security.Enforce")
+
+	oapi := api.OpenAPI()
+	c := &config
+	if c.Realm == "" && oapi.Info != nil {
+		c.Realm = oapi.Info.Title
+	}
+
+	check := func(oapi *huma.OpenAPI, op *huma.Operation) {
+		reqs := requirements(oapi, op)
+		anonymous := len(reqs) == 0
+		for _, req := range reqs {
+			if len(req) == 0 {
+				anonymous = true
+			}
+			for name := range req {
+				if err := c.supports(schemeFor(oapi, name)); err != nil {
+					panic(fmt.Sprintf("operation %s security scheme %q: %v", op.OperationID, name, err))
+				}
+			}
+		}
+		if !anonymous {
+			document(oapi, op)
+		}
+	}
+	oapi.OnAddOperation = append(oapi.OnAddOperation, check)
+
+	api.UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+		reqs := requirements(oapi, ctx.Operation())
+		if len(reqs) == 0 {
+			next(ctx)
+			return
+		}
+
+		principals, failed := c.verify(oapi, ctx, reqs)
+		if principals != nil {
+			next(huma.WithValue(ctx, principalsKey{}, principals))
+			return
+		}
+
+		if failed.status == http.StatusUnauthorized {
+			for _, challenge := range c.challenges(oapi, reqs, failed) {
+				ctx.AppendHeader("WWW-Authenticate", challenge)
+			}
+		} else if failed.scopes != nil {
+			ctx.SetHeader("WWW-Authenticate", fmt.Sprintf(`Bearer realm=%q, error="insufficient_scope", scope=%q`, c.Realm, strings.Join(failed.scopes, " ")))
+		}
+		huma.WriteErr(api, ctx, failed.status, failed.msg)
+	})
+}
+
+// document adds the `401 Unauthorized` and `403 Forbidden` responses to the
+// operation.
+func document(oapi *huma.OpenAPI, op *huma.Operation) {
+	if op.Responses == nil {
+		op.Responses = map[string]*huma.Response{}
+	}
+	for _, status := range []int{http.StatusUnauthorized, http.StatusForbidden} {
+		code := strconv.Itoa(status)
+		if op.Responses[code] == nil {
+			op.Responses[code] = huma.ErrorResponse(oapi.Components.Schemas, op, status)
+		}
+	}
+}
+
+// Principal returns the principal authenticated for the request, or `nil` if
+// the operation allows anonymous access. If the satisfied requirement
+// combines several schemes, then the principal for the first scheme by name
+// is returned. See `Principals` to get all of them.
+func Principal(ctx context.Context) any {
+	principals := Principals(ctx)
+	names := make([]string, 0, len(principals))
+	for name := range principals {
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+	return principals[names[0]]
+}
+
+// Principals returns the principals authenticated for the request by
+// security scheme name.
+func Principals(ctx context.Context) map[string]any {
+	principals, _ := ctx.Value(principalsKey{}).(map[string]any)
+	return principals
+}
+
+// requirements returns the operation's security requirements, falling back
+// to the global requirements if the operation doesn't set any.
+func requirements(oapi *huma.OpenAPI, op *huma.Operation) []map[string][]string {
+	if op == nil {
+		return nil
+	}
+	if op.Security != nil {
+		return op.Security
+	}
+	return oapi.Security
+}
+
+func schemeFor(oapi *huma.OpenAPI, name string) *huma.SecurityScheme {
+	if oapi.Components == nil {
+		return nil
+	}
+	return oapi.Components.SecuritySchemes[name]
+}
+
+// supports returns an error if the scheme can't be verified.
+func (c *Config) supports(scheme *huma.SecurityScheme) error {
+	if scheme == nil {
+		return errors.New("not found in components")
+	}
+	var ok bool
+	switch scheme.Type {
+	case "apiKey":
+		ok = c.APIKey != nil
+	case "http":
+		switch strings.ToLower(scheme.Scheme) {
+		case "basic":
+			ok = c.Basic != nil
+		case "bearer":
+			ok = c.Bearer != nil
+		default:
+			return fmt.Errorf("unsupported http scheme %q", scheme.Scheme)
+		}
+	case "oauth2", "openIdConnect":
+		ok = c.OAuth2 != nil
+	default:
+		return fmt.Errorf("unsupported type %q", scheme.Type)
+	}
+	if !ok {
+		return fmt.Errorf("no verifier configured for %s", scheme.Type)
+	}
+	return nil
+}
+
+// verify checks each requirement in turn, returning the principals for the
+// first one which is satisfied. Otherwise, the most relevant error is
+// returned, preferring forbidden over invalid over missing credentials.
+func (c *Config) verify(oapi *huma.OpenAPI, ctx huma.Context, reqs []map[string][]string) (map[string]any, *authError) {
+	var failed *authError
+	for _, req := range reqs {
+		names := make([]string, 0, len(req))
+		for name := range req {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		principals := map[string]any{}
+		var err *authError
+		for _, name := range names {
+			var principal any
+			principal, err = c.verifyScheme(ctx, name, schemeFor(oapi, name), req[name])
+			if err != nil {
+				err.scheme = name
+				break
+			}
+			principals[name] = principal
+		}
+		if err == nil {
+			return principals, nil
+		}
+		if failed == nil || err.status > failed.status || (err.status == failed.status && err.invalid && !failed.invalid) {
+			failed = err
+		}
+	}
+	return nil, failed
+}
+
+// verifyScheme reads the credentials for a single scheme from the request and
+// calls the matching verifier.
+func (c *Config) verifyScheme(ctx huma.Context, name string, scheme *huma.SecurityScheme, scopes []string) (any, *authError) {
+	if scheme == nil {
+		// Hidden operations are not checked when registered.
+		return nil, &authError{status: http.StatusUnauthorized, msg: "unknown security scheme " + name}
+	}
+
+	var principal any
+	var err error
+	switch scheme.Type {
+	case "apiKey":
+		key := ""
+		switch scheme.In {
+		case "header":
+			key = ctx.Header(scheme.Name)
+		case "query":
+			key = ctx.Query(scheme.Name)
+		case "cookie":
+			if cookie, cerr := huma.ReadCookie(ctx, scheme.Name); cerr == nil {
+				key = cookie.Value
+			}
+		}
+		if key == "" || c.APIKey == nil {
+			return nil, &authError{status: http.StatusUnauthorized, msg: "missing API key"}
+		}
+		principal, err = c.APIKey(ctx.Context(), name, key)
+	case "http":
+		if strings.EqualFold(scheme.Scheme, "basic") {
+			username, password, ok := basicAuth(ctx.Header("Authorization"))
+			if !ok || c.Basic == nil {
+				return nil, &authError{status: http.StatusUnauthorized, msg: "missing credentials"}
+			}
+			principal, err = c.Basic(ctx.Context(), name, username, password)
+		} else {
+			token := bearerToken(ctx.Header("Authorization"))
+			if token == "" || c.Bearer == nil {
+				return nil, &authError{status: http.StatusUnauthorized, msg: "missing bearer token"}
+			}
+			principal, err = c.Bearer(ctx.Context(), name, token)
+		}
+	case "oauth2", "openIdConnect":
+		token := bearerToken(ctx.Header("Authorization"))
+		if token == "" || c.OAuth2 == nil {
+			return nil, &authError{status: http.StatusUnauthorized, msg: "missing bearer token"}
+		}
+		principal, err = c.OAuth2(ctx.Context(), name, token, scopes)
+		if errors.Is(err, ErrInsufficientScope) {
+			return nil, &authError{status: http.StatusForbidden, msg: "insufficient scope", scopes: scopes}
+		}
+	default:
+		return nil, &authError{status: http.StatusUnauthorized, msg: "unsupported security scheme " + name}
+	}
+
+	if err != nil {
+		var se huma.StatusError
+		if errors.As(err, &se) && se.GetStatus() == http.StatusForbidden {
+			return nil, &authError{status: http.StatusForbidden, msg: se.Error()}
+		}
+		return nil, &authError{status: http.StatusUnauthorized, msg: "invalid credentials", invalid: true}
+	}
+	return principal, nil
+}
+
+// challenges returns the `WWW-Authenticate` challenges for the HTTP auth
+// schemes referenced by the requirements. API keys have no standard
+// challenge.
+func (c *Config) challenges(oapi *huma.OpenAPI, reqs []map[string][]string, failed *authError) []string {
+	seen := map[string]bool{}
+	challenges := []string{}
+	add := func(challenge string) {
+		if !seen[challenge] {
+			seen[challenge] = true
+			challenges = append(challenges, challenge)
+		}
+	}
+	for _, req := range reqs {
+		names := make([]string, 0, len(req))
+		for name := range req {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			scheme := schemeFor(oapi, name)
+			if scheme == nil {
+				continue
+			}
+			switch {
+			case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "basic"):
+				add(fmt.Sprintf("Basic realm=%q", c.Realm))
+			case scheme.Type == "http" || scheme.Type == "oauth2" || scheme.Type == "openIdConnect":
+				if failed.invalid && failed.scheme == name {
+					add(fmt.Sprintf(`Bearer realm=%q, error="invalid_token"`, c.Realm))
+				} else {
+					add(fmt.Sprintf("Bearer realm=%q", c.Realm))
+				}
+			}
+		}
+	}
+	return challenges
+}
+
+// basicAuth parses the `Authorization` header for basic auth credentials.
+func basicAuth(auth string) (username, password string, ok bool) {
+	prefix, encoded, found := strings.Cut(auth, " ")
+	if !found || !strings.EqualFold(prefix, "Basic") {
+		return "", "", false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", "", false
+	}
+	return strings.Cut(string(decoded), ":")
+}
+
+// bearerToken returns the token from a bearer `Authorization` header.
+func bearerToken(auth string) string {
+	prefix, token, found := strings.Cut(auth, " ")
+	if !found || !strings.EqualFold(prefix, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
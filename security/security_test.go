@@ -0,0 +1,238 @@
+package security
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+)
+
+type User struct {
+	Name string
+}
+
+func newAPI(t *testing.T) humatest.TestAPI {
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.Components.SecuritySchemes = map[string]*huma.SecurityScheme{
+		"key":    {Type: "apiKey", In: "header", Name: "X-API-Key"},
+		"cookie": {Type: "apiKey", In: "cookie", Name: "session"},
+		"basic":  {Type: "http", Scheme: "basic"},
+		"bearer": {Type: "http", Scheme: "bearer"},
+		"oauth":  {Type: "oauth2", Flows: &huma.OAuthFlows{}},
+	}
+	config.Security = []map[string][]string{{"bearer": {}}}
+	_, api := humatest.New(t, config)
+
+	Enforce(api, Config{
+		APIKey: func(ctx context.Context, scheme, key string) (any, error) {
+			if key == "secret" {
+				return &User{Name: scheme}, nil
+			}
+			return nil, errors.New("bad key")
+		},
+		Basic: func(ctx context.Context, scheme, username, password string) (any, error) {
+			if password == "pass" {
+				return &User{Name: username}, nil
+			}
+			return nil, errors.New("bad password")
+		},
+		Bearer: func(ctx context.Context, scheme, token string) (any, error) {
+			switch token {
+			case "banned":
+				return nil, huma.Error403Forbidden("account suspended")
+			case "token":
+				return &User{Name: "bearer"}, nil
+			}
+			return nil, errors.New("bad token")
+		},
+		OAuth2: func(ctx context.Context, scheme, token string, scopes []string) (any, error) {
+			if token != "token" {
+				return nil, errors.New("bad token")
+			}
+			for _, s := range scopes {
+				if s != "read" {
+					return nil, ErrInsufficientScope
+				}
+			}
+			return &User{Name: "oauth"}, nil
+		},
+	})
+	return api
+}
+
+func register(api huma.API, id string, security []map[string][]string) {
+	huma.Register(api, huma.Operation{
+		OperationID: id,
+		Method:      http.MethodGet,
+		Path:        "/" + id,
+		Security:    security,
+	}, func(ctx context.Context, input *struct{}) (*struct{ Body []string }, error) {
+		names := []string{}
+		for scheme, p := range Principals(ctx) {
+			names = append(names, scheme+":"+p.(*User).Name)
+		}
+		return &struct{ Body []string }{Body: names}, nil
+	})
+}
+
+func basic(user, pass string) string {
+	return "Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+pass))
+}
+
+func TestEnforce(t *testing.T) {
+	api := newAPI(t)
+	register(api, "global", nil)
+	register(api, "public", []map[string][]string{})
+	register(api, "optional", []map[string][]string{{"key": {}}, {}})
+	register(api, "either", []map[string][]string{{"key": {}}, {"basic": {}}})
+	register(api, "both", []map[string][]string{{"key": {}, "basic": {}}})
+	register(api, "cookie", []map[string][]string{{"cookie": {}}})
+	register(api, "scoped", []map[string][]string{{"oauth": {"read", "write"}}})
+
+	// Global requirements apply when the operation has none.
+	resp := api.Get("/global")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	assert.Equal(t, `Bearer realm="Test API"`, resp.Header().Get("WWW-Authenticate"))
+	assert.Equal(t, "application/problem+json", resp.Header().Get("Content-Type"))
+
+	resp = api.Get("/global", "Authorization: Bearer nope")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	assert.Equal(t, `Bearer realm="Test API", error="invalid_token"`, resp.Header().Get("WWW-Authenticate"))
+
+	resp = api.Get("/global", "Authorization: Bearer banned")
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+	assert.Contains(t, resp.Body.String(), "account suspended")
+
+	resp = api.Get("/global", "Authorization: bearer token")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `["bearer:bearer"]`, resp.Body.String())
+
+	// An empty list disables the global requirements.
+	assert.Equal(t, http.StatusOK, api.Get("/public").Code)
+
+	// An empty requirement allows anonymous access.
+	assert.Equal(t, http.StatusOK, api.Get("/optional").Code)
+
+	// Any one of the requirements may be satisfied.
+	resp = api.Get("/either")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	assert.Equal(t, []string{`Basic realm="Test API"`}, resp.Header().Values("WWW-Authenticate"))
+
+	resp = api.Get("/either", "X-API-Key: secret")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `["key:key"]`, resp.Body.String())
+
+	resp = api.Get("/either", basic("alice", "pass"))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `["basic:alice"]`, resp.Body.String())
+
+	// All schemes of a requirement must be satisfied.
+	resp = api.Get("/both", "X-API-Key: secret")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp = api.Get("/both", "X-API-Key: secret", basic("alice", "wrong"))
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp = api.Get("/both", "X-API-Key: secret", basic("alice", "pass"))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.ElementsMatch(t, []string{"basic:alice", "key:key"}, decode(t, api, resp.Body.Bytes()))
+
+	resp = api.Get("/cookie", "Cookie: session=secret")
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	// OAuth2 scopes are passed to the verifier.
+	resp = api.Get("/scoped", "Authorization: Bearer token")
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+	assert.Equal(t, `Bearer realm="Test API", error="insufficient_scope", scope="read write"`, resp.Header().Get("WWW-Authenticate"))
+}
+
+func TestOpenAPI(t *testing.T) {
+	api := newAPI(t)
+	register(api, "global", nil)
+	register(api, "public", []map[string][]string{})
+	register(api, "optional", []map[string][]string{{"key": {}}, {}})
+
+	// Secured operations document their auth errors.
+	op := api.OpenAPI().Paths["/global"].Get
+	assert.Contains(t, op.Responses, "401")
+	assert.Contains(t, op.Responses, "403")
+	assert.Contains(t, op.Responses["401"].Content, "application/problem+json")
+
+	// Operations allowing anonymous access don't.
+	for _, path := range []string{"/public", "/optional"} {
+		op = api.OpenAPI().Paths[path].Get
+		assert.NotContains(t, op.Responses, "401")
+		assert.NotContains(t, op.Responses, "403")
+	}
+}
+
+func decode(t *testing.T, api huma.API, body []byte) []string {
+	var names []string
+	assert.NoError(t, api.Unmarshal("application/json", body, &names))
+	return names
+}
+
+func TestPrincipal(t *testing.T) {
+	api := newAPI(t)
+	huma.Register(api, huma.Operation{
+		OperationID: "me",
+		Method:      http.MethodGet,
+		Path:        "/me",
+	}, func(ctx context.Context, input *struct{}) (*struct{ Body string }, error) {
+		return &struct{ Body string }{Body: Principal(ctx).(*User).Name}, nil
+	})
+
+	resp := api.Get("/me", "Authorization: Bearer token")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, `"bearer"`+"\n", resp.Body.String())
+
+	assert.Nil(t, Principal(context.Background()))
+}
+
+func TestUnknownSchemePanics(t *testing.T) {
+	api := newAPI(t)
+	assert.PanicsWithValue(t, `operation missing security scheme "missing": not found in components`, func() {
+		register(api, "missing", []map[string][]string{{"missing": {}}})
+	})
+
+	_, api = humatest.New(t)
+	api.OpenAPI().Components.SecuritySchemes = map[string]*huma.SecurityScheme{
+		"bearer": {Type: "http", Scheme: "bearer"},
+	}
+	Enforce(api, Config{})
+	assert.Panics(t, func() {
+		register(api, "no-verifier", []map[string][]string{{"bearer": {}}})
+	})
+}
+
+func TestEnforceAfterRegisterPanics(t *testing.T) {
+	// Existing operations would be left unprotected by the middleware.
+	_, api := humatest.New(t)
+	api.OpenAPI().Components.SecuritySchemes = map[string]*huma.SecurityScheme{
+		"bearer": {Type: "http", Scheme: "bearer"},
+	}
+	register(api, "early", []map[string][]string{{"bearer": {}}})
+	assert.PanicsWithValue(t, "security.Enforce must be set up before registering operations, found /early", func() {
+		Enforce(api, Config{})
+	})
+
+	// Groups only consider their own operations.
+	grp := huma.NewGroup(api, "/admin")
+	Enforce(grp, Config{Bearer: func(ctx context.Context, scheme, token string) (any, error) {
+		return &User{Name: token}, nil
+	}})
+	register(grp, "users", []map[string][]string{{"bearer": {}}})
+	assert.Equal(t, http.StatusUnauthorized, api.Get("/admin/users").Code)
+
+	// Prefixes match whole path segments.
+	register(api, "v10", nil)
+	assert.NotPanics(t, func() {
+		Enforce(huma.NewGroup(api, "/v1"), Config{})
+	})
+}
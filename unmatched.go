@@ -0,0 +1,94 @@
+package huma
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// HandleUnmatched writes an error response for a request which did not match
+// any registered operation. If the request path matches a path in the
+// OpenAPI, then the method is not supported and a `405 Method Not Allowed`
+// is written with an `Allow` header listing the path's methods. Otherwise, a
+// `404 Not Found` is written. Both use the API's configured error model.
+//
+// Each adapter package provides an opt-in function to call this from the
+// router's not found and method not allowed handlers, e.g.
+// `humachi.HandleUnmatched`, so that unmatched routes are handled the same way
+// regardless of the router. The context's operation is `nil`.
+func HandleUnmatched(api API, ctx Context) {
+	u := ctx.URL()
+	if item := findPathItem(api.OpenAPI(), u.Path); item != nil {
+		if methods := pathMethods(item); len(methods) > 0 {
+			ctx.SetHeader("Allow", strings.Join(methods, ", "))
+			WriteErr(api, ctx, http.StatusMethodNotAllowed, fmt.Sprintf("method %s not allowed", ctx.Method()))
+			return
+		}
+	}
+	WriteErr(api, ctx, http.StatusNotFound, "no operation found for the request path")
+}
+
+// pathMethods returns the HTTP methods of the operations on a path.
+func pathMethods(item *PathItem) []string {
+	methods := []string{}
+	for _, op := range []*Operation{item.Get, item.Head, item.Post, item.Put, item.Patch, item.Delete, item.Options, item.Trace} {
+		if op != nil {
+			methods = append(methods, op.Method)
+		}
+	}
+	return methods
+}
+
+// findPathItem finds the OpenAPI path item which matches a request path,
+// which may include the API prefix from the first server URL.
+func findPathItem(oapi *OpenAPI, path string) *PathItem {
+	if item := matchPathItem(oapi.Paths, path); item != nil {
+		return item
+	}
+	if prefix := getAPIPrefix(oapi); prefix != "" && strings.HasPrefix(path, prefix) {
+		return matchPathItem(oapi.Paths, strings.TrimPrefix(path, prefix))
+	}
+	return nil
+}
+
+// matchPathItem matches a request path against path templates like
+// `/things/{id}` or `/files/{path...}`. When several templates match, the
+// one with the fewest path params wins, e.g. `/things/special` over
+// `/things/{id}`.
+func matchPathItem(paths map[string]*PathItem, path string) *PathItem {
+	if item := paths[path]; item != nil {
+		return item
+	}
+
+	segments := strings.Split(path, "/")
+	var found *PathItem
+	foundParams := -1
+outer:
+	for template, item := range paths {
+		parts := strings.Split(template, "/")
+		last := parts[len(parts)-1]
+		wildcard := strings.HasPrefix(last, "{") && strings.HasSuffix(last, "...}")
+		if (!wildcard && len(parts) != len(segments)) || (wildcard && len(segments) < len(parts)) {
+			continue
+		}
+		params := 0
+		for i, part := range parts {
+			if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+				// A wildcard may be empty, but other params may not.
+				if segments[i] == "" && !(wildcard && i == len(parts)-1) {
+					continue outer
+				}
+				params++
+				continue
+			}
+			if part != segments[i] {
+				continue outer
+			}
+		}
+		if found == nil || params < foundParams {
+			found = item
+			foundParams = params
+		}
+	}
+	return found
+}
@@ -0,0 +1,104 @@
+package huma
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// formContentType is the media type used by HTML forms and many webhook
+// providers to send a body of URL encoded key/value pairs.
+const formContentType = "application/x-www-form-urlencoded"
+
+// isFormContentType returns whether the given `Content-Type` header value is
+// for a URL encoded form, ignoring any parameters like `charset`.
+func isFormContentType(contentType string) bool {
+	if i := strings.IndexByte(contentType, ';'); i != -1 {
+		contentType = contentType[:i]
+	}
+	return strings.EqualFold(strings.TrimSpace(contentType), formContentType)
+}
+
+// formFieldNames returns a map of form keys to schema property names for the
+// fields of a struct type. The `form` tag sets the key, falling back to the
+// JSON field name. Embedded structs are included.
+func formFieldNames(t reflect.Type) map[string]string {
+	t = deref(t)
+	names := map[string]string{}
+	if t.Kind() != reflect.Struct {
+		return names
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name := f.Name
+		if j := f.Tag.Get("json"); j != "" {
+			if j == "-" {
+				continue
+			}
+			if n := strings.Split(j, ",")[0]; n != "" {
+				name = n
+			}
+		} else if f.Anonymous {
+			for k, v := range formFieldNames(f.Type) {
+				names[k] = v
+			}
+			continue
+		}
+
+		key := name
+		if form := f.Tag.Get("form"); form != "" {
+			key = form
+		}
+		names[key] = name
+	}
+	return names
+}
+
+// parseFormBody parses a URL encoded form body and converts it into JSON
+// using the types described by the body schema, so that it can then be
+// validated and unmarshaled like any other body. Repeated keys are used for
+// array fields. Values which cannot be converted are left as strings for
+// validation to report.
+func parseFormBody(r Registry, s *Schema, names map[string]string, body []byte) ([]byte, error) {
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+
+	for s != nil && s.Ref != "" {
+		s = r.SchemaFromRef(s.Ref)
+	}
+
+	parsed := make(map[string]any, len(values))
+	for key, vals := range values {
+		if len(vals) == 0 {
+			continue
+		}
+		name := key
+		if n, ok := names[key]; ok {
+			name = n
+		}
+
+		var value any = vals[0]
+		if len(vals) > 1 {
+			value = vals
+		} else if s != nil {
+			ps := s.Properties[name]
+			for ps != nil && ps.Ref != "" {
+				ps = r.SchemaFromRef(ps.Ref)
+			}
+			if ps != nil && ps.Type == TypeArray {
+				// A single value is a single item, even if it contains commas.
+				value = vals
+			}
+		}
+		parsed[name] = value
+	}
+
+	return json.Marshal(coerceDeepObject(r, s, parsed))
+}
@@ -0,0 +1,268 @@
+package huma
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// VersionInfo describes a single API version in the version index.
+type VersionInfo struct {
+	// Prefix is the path prefix the version is served under, e.g. `/v1`.
+	Prefix string `json:"prefix"`
+
+	// Title is the title of the API from its OpenAPI info.
+	Title string `json:"title,omitempty"`
+
+	// Version is the version of the API from its OpenAPI info.
+	Version string `json:"version,omitempty"`
+
+	// OpenAPI is the URL of the version's OpenAPI document, if served.
+	OpenAPI string `json:"openapi,omitempty"`
+
+	// Docs is the URL of the version's documentation, if served.
+	Docs string `json:"docs,omitempty"`
+}
+
+// Versions serves several versions of an API from a single router. Each
+// version is a separate API with its own OpenAPI document, schema registry,
+// and spec, docs and schema URLs, all served under the version's path prefix.
+//
+//	router := chi.NewMux()
+//	versions := huma.NewVersions(humachi.NewAdapter(router), "/versions")
+//	v1 := versions.Add("/v1", huma.DefaultConfig("My API", "1.0.0"))
+//	v2 := versions.Add("/v2", huma.DefaultConfig("My API", "2.0.0"))
+//
+//	// Register an operation on a single version.
+//	huma.Register(v2, huma.Operation{...}, handler)
+//
+//	// Register an operation on all versions.
+//	huma.RegisterAll(versions.APIs(), huma.Operation{...}, handler)
+type Versions struct {
+	adapter Adapter
+	apis    []API
+	infos   []VersionInfo
+}
+
+// NewVersions creates a new set of API versions which share the given router
+// adapter. If `indexPath` is not empty, a version index listing each version
+// and its spec and docs URLs is served at that path as JSON.
+func NewVersions(adapter Adapter, indexPath string) *Versions {
+	v := &Versions{adapter: adapter, infos: []VersionInfo{}}
+	if indexPath != "" {
+		adapter.Handle(&Operation{
+			Method: http.MethodGet,
+			Path:   indexPath,
+		}, func(ctx Context) {
+			ctx.SetHeader("Content-Type", "application/json")
+			b, _ := json.Marshal(v.infos)
+			ctx.BodyWriter().Write(b)
+		})
+	}
+	return v
+}
+
+// Add creates a new API version served under the path prefix, e.g. `/v1`.
+// The config must not be shared with other versions, so each version gets its
+// own OpenAPI document and schema registry. The prefix is added to the
+// OpenAPI servers so that generated docs and schema links include it.
+func (v *Versions) Add(prefix string, config Config) API {
+	prefix = strings.TrimSuffix(prefix, "/")
+	if prefix == "" {
+		panic("version prefix must not be empty")
+	}
+	for _, api := range v.apis {
+		if config.OpenAPI != nil && api.OpenAPI() == config.OpenAPI {
+			panic("API versions must not share an OpenAPI")
+		}
+	}
+
+	if config.OpenAPI == nil {
+		config.OpenAPI = &OpenAPI{}
+	}
+	oapi := config.OpenAPI
+	if len(oapi.Servers) == 0 {
+		oapi.Servers = []*Server{{URL: prefix}}
+	} else {
+		// Copy the servers, which may be shared with the caller's other
+		// configs, before adding the prefix.
+		servers := make([]*Server, len(oapi.Servers))
+		for i, s := range oapi.Servers {
+			server := *s
+			server.URL = strings.TrimSuffix(s.URL, "/") + prefix
+			servers[i] = &server
+		}
+		oapi.Servers = servers
+	}
+
+	api := NewAPI(config, &prefixAdapter{Adapter: v.adapter, prefix: prefix})
+
+	info := VersionInfo{Prefix: prefix}
+	if oapi.Info != nil {
+		info.Title = oapi.Info.Title
+		info.Version = oapi.Info.Version
+	}
+	if config.OpenAPIPath != "" {
+		info.OpenAPI = prefix + config.OpenAPIPath + ".json"
+	}
+	if config.DocsPath != "" {
+		info.Docs = prefix + config.DocsPath
+	}
+	v.apis = append(v.apis, api)
+	v.infos = append(v.infos, info)
+	return api
+}
+
+// APIs returns all of the API versions in the order they were added.
+func (v *Versions) APIs() []API {
+	return append([]API{}, v.apis...)
+}
+
+// Info returns the version index entries in the order they were added.
+func (v *Versions) Info() []VersionInfo {
+	return append([]VersionInfo{}, v.infos...)
+}
+
+// prefixAdapter is a router adapter which serves all operations under a path
+// prefix.
+type prefixAdapter struct {
+	Adapter
+	prefix string
+}
+
+func (a *prefixAdapter) Handle(op *Operation, handler func(Context)) {
+	routed := *op
+	routed.Path = a.prefix + op.Path
+	a.Adapter.Handle(&routed, func(ctx Context) {
+		// Handlers see the operation as documented, without the prefix.
+		handler(&prefixContext{humaContext: ctx, op: op})
+	})
+}
+
+// prefixContext is a context which returns the un-prefixed operation.
+type prefixContext struct {
+	humaContext
+	op *Operation
+}
+
+func (c *prefixContext) Operation() *Operation {
+	return c.op
+}
+
+// RegisterAll registers the same operation and handler with each of the given
+// APIs, e.g. with all of the versions from `Versions.APIs()`. Each API gets
+// its own copy of the operation, including its parameters, request body,
+// responses, security requirements, middlewares and metadata, so modifiers
+// and `Register` can't leak changes from one API into another. Schemas set
+// on the operation are shared.
+func RegisterAll[I, O any](apis []API, op Operation, handler func(context.Context, *I) (*O, error)) {
+	for _, api := range apis {
+		Register(api, copyOperation(op), handler)
+	}
+}
+
+// copyOperation returns a copy of the operation which shares no slices, maps
+// or documentation structs with the original, except for schemas.
+func copyOperation(op Operation) Operation {
+	op.Tags = cloneSlice(op.Tags)
+	op.Errors = cloneSlice(op.Errors)
+	op.Middlewares = cloneSlice(op.Middlewares)
+	op.ErrorModels = cloneMap(op.ErrorModels)
+	op.Metadata = cloneMap(op.Metadata)
+	op.Extensions = cloneMap(op.Extensions)
+	op.Callbacks = cloneMap(op.Callbacks)
+	if op.ExternalDocs != nil {
+		docs := *op.ExternalDocs
+		op.ExternalDocs = &docs
+	}
+	if op.Parameters != nil {
+		params := make([]*Param, len(op.Parameters))
+		for i, p := range op.Parameters {
+			if p != nil {
+				param := *p
+				p = &param
+			}
+			params[i] = p
+		}
+		op.Parameters = params
+	}
+	if op.RequestBody != nil {
+		body := *op.RequestBody
+		body.Content = cloneContent(body.Content)
+		op.RequestBody = &body
+	}
+	if op.Responses != nil {
+		responses := make(map[string]*Response, len(op.Responses))
+		for code, r := range op.Responses {
+			if r != nil {
+				resp := *r
+				resp.Headers = cloneMap(resp.Headers)
+				resp.Content = cloneContent(resp.Content)
+				r = &resp
+			}
+			responses[code] = r
+		}
+		op.Responses = responses
+	}
+	if op.Security != nil {
+		security := make([]map[string][]string, len(op.Security))
+		for i, req := range op.Security {
+			if req != nil {
+				security[i] = make(map[string][]string, len(req))
+				for name, scopes := range req {
+					security[i][name] = cloneSlice(scopes)
+				}
+			}
+		}
+		op.Security = security
+	}
+	if op.Servers != nil {
+		servers := make([]*Server, len(op.Servers))
+		for i, s := range op.Servers {
+			if s != nil {
+				server := *s
+				s = &server
+			}
+			servers[i] = s
+		}
+		op.Servers = servers
+	}
+	return op
+}
+
+// cloneContent copies a content map and its media types.
+func cloneContent(content map[string]*MediaType) map[string]*MediaType {
+	if content == nil {
+		return nil
+	}
+	copied := make(map[string]*MediaType, len(content))
+	for ct, mt := range content {
+		if mt != nil {
+			m := *mt
+			mt = &m
+		}
+		copied[ct] = mt
+	}
+	return copied
+}
+
+// cloneSlice returns a shallow copy of s, keeping nil as nil.
+func cloneSlice[S ~[]E, E any](s S) S {
+	if s == nil {
+		return nil
+	}
+	return append(S{}, s...)
+}
+
+// cloneMap returns a shallow copy of m, keeping nil as nil.
+func cloneMap[M ~map[K]V, K comparable, V any](m M) M {
+	if m == nil {
+		return nil
+	}
+	copied := make(M, len(m))
+	for k, v := range m {
+		copied[k] = v
+	}
+	return copied
+}
@@ -0,0 +1,159 @@
+package huma_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+)
+
+func TestVersions(t *testing.T) {
+	versions := huma.NewVersions(humatest.NewAdapter(), "/versions")
+	v1 := humatest.Wrap(t, versions.Add("/v1", huma.DefaultConfig("Test API", "1.0.0")))
+	v2 := humatest.Wrap(t, versions.Add("/v2/", huma.DefaultConfig("Test API", "2.0.0")))
+
+	type ThingV1 struct {
+		Name string `json:"name"`
+	}
+	type ThingV2 struct {
+		Name  string `json:"name"`
+		Color string `json:"color"`
+	}
+
+	v1.UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+		// Operations are seen as documented, without the version prefix.
+		assert.NotContains(t, ctx.Operation().Path, "/v1")
+		next(ctx)
+	})
+
+	huma.Get(v1, "/things", func(ctx context.Context, input *struct{}) (*struct{ Body ThingV1 }, error) {
+		return &struct{ Body ThingV1 }{Body: ThingV1{Name: "one"}}, nil
+	})
+	huma.Get(v2, "/things", func(ctx context.Context, input *struct{}) (*struct{ Body ThingV2 }, error) {
+		return &struct{ Body ThingV2 }{Body: ThingV2{Name: "two", Color: "blue"}}, nil
+	})
+
+	// Shared operations can be registered with all versions at once.
+	huma.RegisterAll(versions.APIs(), huma.Operation{
+		OperationID: "get-health",
+		Method:      http.MethodGet,
+		Path:        "/health",
+	}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	// Each version has its own OpenAPI document.
+	assert.Equal(t, "/v1", v1.OpenAPI().Servers[0].URL)
+	assert.Equal(t, "/v2", v2.OpenAPI().Servers[0].URL)
+	assert.Contains(t, v1.OpenAPI().Paths, "/things")
+	assert.Contains(t, v1.OpenAPI().Paths, "/health")
+	assert.Contains(t, v2.OpenAPI().Paths, "/health")
+	assert.Contains(t, v1.OpenAPI().Components.Schemas.Map(), "ThingV1")
+	assert.NotContains(t, v2.OpenAPI().Components.Schemas.Map(), "ThingV1")
+
+	resp := v1.Get("/v1/things", "Host: localhost")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"$schema": "http://localhost/v1/schemas/ThingV1.json", "name": "one"}`, resp.Body.String())
+
+	resp = v2.Get("/v2/things")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), "blue")
+
+	assert.Equal(t, http.StatusNoContent, v2.Get("/v2/health").Code)
+
+	// Spec, docs & schema URLs don't collide.
+	resp = v1.Get("/v1/openapi.json")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"version":"1.0.0"`)
+
+	resp = v2.Get("/v2/openapi.json")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"version":"2.0.0"`)
+
+	resp = v2.Get("/v2/docs")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), "/v2/openapi.yaml")
+
+	resp = v2.Get("/v2/schemas/ThingV2.json")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), "color")
+
+	resp = v1.Get("/versions")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `[
+		{"prefix": "/v1", "title": "Test API", "version": "1.0.0", "openapi": "/v1/openapi.json", "docs": "/v1/docs"},
+		{"prefix": "/v2", "title": "Test API", "version": "2.0.0", "openapi": "/v2/openapi.json", "docs": "/v2/docs"}
+	]`, resp.Body.String())
+}
+
+func TestVersionsSharedConfigPanics(t *testing.T) {
+	versions := huma.NewVersions(humatest.NewAdapter(), "")
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	versions.Add("/v1", config)
+
+	assert.Panics(t, func() {
+		versions.Add("/v2", config)
+	})
+	require.Len(t, versions.APIs(), 1)
+}
+
+func TestRegisterAllCopies(t *testing.T) {
+	versions := huma.NewVersions(humatest.NewAdapter(), "")
+	v1 := huma.NewGroup(versions.Add("/v1", huma.DefaultConfig("Test API", "1.0.0")), "")
+	v1.UseModifier(func(op *huma.Operation) {
+		op.Tags = append(op.Tags, "v1")
+		op.Metadata["v1"] = true
+		op.Responses["200"].Description = "Thing from v1"
+		op.Parameters[0].Description = "v1 filter"
+	})
+	v2 := versions.Add("/v2", huma.DefaultConfig("Test API", "2.0.0"))
+
+	tags := make([]string, 1, 4)
+	tags[0] = "things"
+	op := huma.Operation{
+		OperationID: "get-thing",
+		Method:      http.MethodGet,
+		Path:        "/thing",
+		Tags:        tags,
+		Metadata:    map[string]any{"shared": true},
+		Parameters:  []*huma.Param{{Name: "filter", In: "query", Schema: &huma.Schema{Type: "string"}}},
+		Responses: map[string]*huma.Response{
+			"200": {Description: "Thing"},
+		},
+	}
+	huma.RegisterAll([]huma.API{v1, v2}, op, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	got := v2.OpenAPI().Paths["/thing"].Get
+	assert.Equal(t, []string{"things"}, got.Tags)
+	assert.NotContains(t, got.Metadata, "v1")
+	assert.Equal(t, "Thing", got.Responses["200"].Description)
+	assert.Empty(t, got.Parameters[0].Description)
+	assert.Equal(t, []string{"things", "v1"}, v1.OpenAPI().Paths["/thing"].Get.Tags)
+
+	// The caller's operation is left untouched.
+	assert.Equal(t, []string{"things"}, op.Tags)
+	assert.Equal(t, map[string]any{"shared": true}, op.Metadata)
+	assert.Equal(t, "Thing", op.Responses["200"].Description)
+	assert.Len(t, op.Responses, 1)
+}
+
+func TestVersionsSharedServers(t *testing.T) {
+	servers := []*huma.Server{{URL: "https://api.example.com/"}}
+	versions := huma.NewVersions(humatest.NewAdapter(), "")
+	for _, prefix := range []string{"/v1", "/v2"} {
+		config := huma.DefaultConfig("Test API", "1.0.0")
+		config.Servers = servers
+		versions.Add(prefix, config)
+	}
+
+	assert.Equal(t, "https://api.example.com/v1", versions.APIs()[0].OpenAPI().Servers[0].URL)
+	assert.Equal(t, "https://api.example.com/v2", versions.APIs()[1].OpenAPI().Servers[0].URL)
+	assert.Equal(t, "https://api.example.com/", servers[0].URL)
+}